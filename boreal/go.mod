@@ -1,18 +0,0 @@
-module boreal
-
-go 1.22.0
-
-toolchain go1.23.1
-
-require (
-	github.com/google/uuid v1.6.0
-	github.com/mattn/go-sqlite3 v1.14.24
-	gorm.io/driver/sqlite v1.5.6
-	gorm.io/gorm v1.25.12
-)
-
-require (
-	github.com/jinzhu/inflection v1.0.0 // indirect
-	github.com/jinzhu/now v1.1.5 // indirect
-	golang.org/x/text v0.19.0 // indirect
-)
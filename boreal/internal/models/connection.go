@@ -1,8 +0,0 @@
-package models
-
-type Connection struct {
-	Name     string
-	Address  string
-	Port     string
-	IsOnline bool
-}
@@ -1,24 +0,0 @@
-package server
-
-import (
-	"boreal/internal/models"
-	"net/http"
-)
-
-func handleGetAirports(w http.ResponseWriter, r *http.Request) {
-	allowCrossOrigin(w, r)
-
-	if r.Method != http.MethodGet {
-		http.Error(w, "only GET allowed", http.StatusMethodNotAllowed)
-		return
-	}
-
-	token := r.Header.Get("Authorization")
-	response := GetAirports(
-		models.Request{
-			Auth: token,
-		},
-	)
-
-	returnResponse(w, r, response)
-}
@@ -1,5 +0,0 @@
-package models
-
-type CancelBuyRequest struct {
-	TicketId uint
-}
@@ -1,5 +0,0 @@
-package models
-
-type FlightsRequest struct {
-	FlightIds []uint
-}
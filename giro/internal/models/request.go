@@ -1,7 +0,0 @@
-package models
-
-type Request struct {
-	Auth string      `json:"Auth"`
-	Data interface{} `json:"Data"`
-}
- 
\ No newline at end of file
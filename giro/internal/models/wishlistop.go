@@ -1,5 +0,0 @@
-package models
-
-type WishlistOperation struct {
-	FlightId uint
-}
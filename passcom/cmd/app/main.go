@@ -0,0 +1,23 @@
+package main
+
+import (
+	"log"
+	"os"
+	"passcom/internal/config"
+	"passcom/internal/server"
+	_ "time/tzdata" // Fusos horários das escalas, mesmo sem o tzdata do sistema
+)
+
+func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	server.Configure(cfg)
+
+	var System = server.GetInstance()
+	err = System.StartServer()
+	if err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
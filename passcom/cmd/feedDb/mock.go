@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"passcom/internal/config"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+func mockAirports(stubs string) {
+	file, err := os.Open(filepath.Join(stubs, "airports.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var airports []models.Airport
+
+	err = json.NewDecoder(file).Decode(&airports)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dao := dao.GetAirportDAO()
+	for _, airport := range airports {
+		dao.Insert(airport)
+		fmt.Printf("%d ", airport.ID)
+	}
+}
+
+func mockClients(stubs string) {
+	file, err := os.Open(filepath.Join(stubs, "clients.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var clients []models.Client
+
+	err = json.NewDecoder(file).Decode(&clients)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dao := dao.GetClientDAO()
+	for _, client := range clients {
+		// Os stubs podem trazer a senha em texto puro ou já com o hash
+		if !utils.IsPasswordHash(client.Password) {
+			hash, err := utils.HashPassword(client.Password)
+			if err != nil {
+				log.Fatal(err)
+			}
+			client.Password = hash
+		}
+		if err := dao.Insert(client); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// migratePasswords replaces the passwords stored in plain text by hashes. Passwords already hashed are kept,
+// so running it again changes nothing; the ones hashed with older parameters are upgraded on the next login.
+func migratePasswords() {
+	dao := dao.GetClientDAO()
+	migrated := 0
+	for _, client := range dao.FindAll() {
+		if utils.IsPasswordHash(client.Password) {
+			continue
+		}
+
+		hash, err := utils.HashPassword(client.Password)
+		if err != nil {
+			log.Fatal(err)
+		}
+		client.Password = hash
+		if err := dao.Update(client); err != nil {
+			log.Fatalf("Password of client %s not migrated: %v", client.Username, err)
+		}
+		migrated++
+	}
+	fmt.Printf("%d passwords migrated\n", migrated)
+}
+
+func mockFlights(stubs string) {
+	file, err := os.Open(filepath.Join(stubs, "flights.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var flights []models.Flight
+
+	err = json.NewDecoder(file).Decode(&flights)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	flightdao := dao.GetFlightDAO()
+	airportdao := dao.GetAirportDAO()
+	for _, flight := range flights {
+		// Os horários vêm do JSON com o fuso horário de cada aeroporto
+		if flight.Scheduled() && !flight.Arrival.After(flight.Departure) {
+			log.Printf("Skipping flight %d -> %d: arrival %v is not after departure %v",
+				flight.OriginAirportID, flight.DestinationAirportID, flight.Arrival, flight.Departure)
+			continue
+		}
+
+		if flight.Capacity == 0 {
+			flight.Capacity = flight.Seats
+		}
+
+		newId, _ := uuid.NewV7()
+		flight.UniqueId = newId.String()
+		src, _ := airportdao.FindById(flight.OriginAirportID)
+		dest, _ := airportdao.FindById(flight.DestinationAirportID)
+		flight.OriginAirport = *src
+		flight.DestinationAirport = *dest
+		flightdao.Insert(flight)
+	}
+}
+
+// Sem argumentos, popula o banco de dados a partir dos stubs. Com "migrate-passwords" antes das flags, apenas
+// substitui as senhas em texto puro de um banco existente pelos seus hashes.
+func main() {
+	args := os.Args[1:]
+	migrate := len(args) > 0 && args[0] == "migrate-passwords"
+	if migrate {
+		args = args[1:]
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	utils.SetDbPath(cfg.DBPath)
+
+	if migrate {
+		migratePasswords()
+		return
+	}
+
+	mockAirports(cfg.StubsPath)
+	mockFlights(cfg.StubsPath)
+	mockClients(cfg.StubsPath)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"passcom/internal/config"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"passcom/internal/utils"
+	"path/filepath"
+	"time"
+	_ "time/tzdata" // Fusos horários das escalas, mesmo sem o tzdata do sistema
+)
+
+// Stores the schedules of stubs/schedules.json and generates their flights for the configured number of days.
+// The server sends the new flights to its peers through the database sync.
+func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	utils.SetDbPath(cfg.DBPath)
+
+	file, err := os.Open(filepath.Join(cfg.StubsPath, "schedules.json"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	var schedules []models.Schedule
+	if err := json.NewDecoder(file).Decode(&schedules); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, schedule := range schedules {
+		if err := schedule.Validate(); err != nil {
+			log.Fatal(err)
+		}
+		dao.GetScheduleDAO().Upsert(schedule)
+	}
+
+	created, err := server.GenerateScheduledFlights(cfg.Company, time.Now(), cfg.ScheduleDays)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("%d flights generated for the next %d days", len(created), cfg.ScheduleDays)
+}
@@ -0,0 +1,256 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the identity of the company served by the process and the paths and addresses it uses.
+// The same binary serves any company, so every value may be overridden at startup.
+type Config struct {
+	Company       string   // Nome da companhia servida
+	Address       string   // Endereço anunciado aos outros servidores (por padrão, o nome da companhia, usado como hostname do container)
+	Port          string   // Porta da API HTTP
+	CLIPort       string   // Porta da CLI TCP
+	DBPath        string   // Caminho do banco de dados SQLite
+	InstancePath  string   // Caminho do arquivo com as variáveis do sistema
+	StubsPath     string   // Pasta com os arquivos JSON usados para popular o banco de dados
+	Peers         []string // Servidores conectados ao iniciar, no formato endereço:porta
+	MinConnection string   // Tempo mínimo entre a chegada de um trecho e a partida do seguinte (ex.: 45m)
+	ScheduleDays  int      // Quantidade de dias à frente para os quais os voos das escalas são gerados
+	AdminToken    string   // Token exigido pela API administrativa (vazio a desativa)
+	Pricing       string   // Motor de preço dos voos próprios (static ou load-factor)
+	TokenAlg      string   // Algoritmo de assinatura dos tokens de acesso (HS256 ou EdDSA)
+	TokenKey      string   // Segredo HMAC ou chave privada Ed25519 em base64 (vazio gera um segredo HMAC aleatório)
+	AccessTTL     string   // Validade dos tokens de acesso (ex.: 15m)
+	RefreshTTL    string   // Tempo sem uso após o qual o refresh token de um dispositivo expira (ex.: 720h)
+	MaxDevices    int      // Quantidade máxima de dispositivos logados por cliente
+	Federation    bool     // Login federado: clientes de outras companhias podem logar neste servidor e vice-versa
+}
+
+// Default returns the configuration used when nothing else is given.
+func Default() Config {
+	return Config{
+		Company:       "rumos",
+		Port:          "7777",
+		CLIPort:       "7770",
+		DBPath:        "database.db",
+		InstancePath:  "systemvars.json",
+		StubsPath:     "stubs",
+		Peers:         make([]string, 0),
+		MinConnection: "45m",
+		ScheduleDays:  30,
+		Pricing:       models.PricingLoadFactor,
+		TokenAlg:      utils.TOKEN_HS256,
+		AccessTTL:     "15m",
+		RefreshTTL:    "720h",
+		MaxDevices:    5,
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence: the defaults, a JSON config file,
+// the environment variables and the command line flags. The config file is given by the -config flag or
+// the CONFIG environment variable.
+//
+// Parameters:
+//   - args: The command line arguments, without the program name.
+//
+// Return:
+//   - The resulting configuration.
+//   - An error if the arguments or the config file are invalid, or if the result is not valid.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("passcom", flag.ContinueOnError)
+	configPath := flags.String("config", os.Getenv("CONFIG"), "path of a JSON config file")
+	company := flags.String("company", "", "name of the company served")
+	address := flags.String("address", "", "address announced to the other servers")
+	port := flags.String("port", "", "port of the HTTP API")
+	cliPort := flags.String("cliport", "", "port of the TCP CLI")
+	dbPath := flags.String("db", "", "path of the SQLite database")
+	instancePath := flags.String("instance", "", "path of the system variables file")
+	stubsPath := flags.String("stubs", "", "folder of the JSON stubs used to feed the database")
+	peers := flags.String("peers", "", "comma-separated address:port of the servers to connect at startup")
+	minConnection := flags.String("min-connection", "", "minimum time between the arrival of a leg and the departure of the next one")
+	adminToken := flags.String("admin-token", "", "token required by the admin API (empty disables it)")
+	pricing := flags.String("pricing", "", "pricing engine of the flights of the company (static or load-factor)")
+	scheduleDays := flags.Int("schedule-days", 0, "number of days ahead for which the flights of the schedules are generated")
+	tokenAlg := flags.String("token-alg", "", "signing algorithm of the access tokens (HS256 or EdDSA)")
+	tokenKey := flags.String("token-key", "", "HMAC secret or base64 Ed25519 private key of the access tokens")
+	accessTTL := flags.String("access-ttl", "", "lifetime of the access tokens")
+	refreshTTL := flags.String("refresh-ttl", "", "idle time after which the refresh token of a device expires")
+	maxDevices := flags.Int("max-devices", 0, "maximum number of devices logged in per client")
+	federation := flags.Bool("federation", false, "let the clients of the connected companies log in on this server, and the other way around")
+
+	if err := flags.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if *configPath != "" {
+		file, err := os.ReadFile(*configPath)
+		if err != nil {
+			return cfg, err
+		}
+		if err := json.Unmarshal(file, &cfg); err != nil {
+			return cfg, fmt.Errorf("invalid config file %s: %w", *configPath, err)
+		}
+	}
+
+	override(&cfg.Company, os.Getenv("COMPANY"))
+	override(&cfg.Address, os.Getenv("ADDRESS"))
+	override(&cfg.Port, os.Getenv("PORT"))
+	override(&cfg.CLIPort, os.Getenv("CLIPORT"))
+	override(&cfg.DBPath, os.Getenv("DB_PATH"))
+	override(&cfg.InstancePath, os.Getenv("INSTANCE_PATH"))
+	override(&cfg.StubsPath, os.Getenv("STUBS_PATH"))
+	override(&cfg.MinConnection, os.Getenv("MIN_CONNECTION"))
+	override(&cfg.AdminToken, os.Getenv("ADMIN_TOKEN"))
+	override(&cfg.Pricing, os.Getenv("PRICING"))
+	override(&cfg.TokenAlg, os.Getenv("TOKEN_ALG"))
+	override(&cfg.TokenKey, os.Getenv("TOKEN_KEY"))
+	override(&cfg.AccessTTL, os.Getenv("ACCESS_TTL"))
+	override(&cfg.RefreshTTL, os.Getenv("REFRESH_TTL"))
+	if env := os.Getenv("PEERS"); env != "" {
+		cfg.Peers = splitList(env)
+	}
+	if env := os.Getenv("SCHEDULE_DAYS"); env != "" {
+		days, err := strconv.Atoi(env)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid SCHEDULE_DAYS %q", env)
+		}
+		cfg.ScheduleDays = days
+	}
+	if env := os.Getenv("MAX_DEVICES"); env != "" {
+		devices, err := strconv.Atoi(env)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid MAX_DEVICES %q", env)
+		}
+		cfg.MaxDevices = devices
+	}
+	if env := os.Getenv("FEDERATION"); env != "" {
+		enabled, err := strconv.ParseBool(env)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid FEDERATION %q", env)
+		}
+		cfg.Federation = enabled
+	}
+
+	override(&cfg.Company, *company)
+	override(&cfg.Address, *address)
+	override(&cfg.Port, *port)
+	override(&cfg.CLIPort, *cliPort)
+	override(&cfg.DBPath, *dbPath)
+	override(&cfg.InstancePath, *instancePath)
+	override(&cfg.StubsPath, *stubsPath)
+	override(&cfg.MinConnection, *minConnection)
+	override(&cfg.AdminToken, *adminToken)
+	override(&cfg.Pricing, *pricing)
+	override(&cfg.TokenAlg, *tokenAlg)
+	override(&cfg.TokenKey, *tokenKey)
+	override(&cfg.AccessTTL, *accessTTL)
+	override(&cfg.RefreshTTL, *refreshTTL)
+	if *peers != "" {
+		cfg.Peers = splitList(*peers)
+	}
+	if *scheduleDays != 0 {
+		cfg.ScheduleDays = *scheduleDays
+	}
+	if *maxDevices != 0 {
+		cfg.MaxDevices = *maxDevices
+	}
+	if *federation {
+		cfg.Federation = true
+	}
+
+	// A porta da CLI era definida com ':' no início
+	cfg.CLIPort = strings.TrimPrefix(cfg.CLIPort, ":")
+	if cfg.Address == "" {
+		cfg.Address = cfg.Company
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// Validate checks that the configuration identifies a company and has valid ports, durations, pricing engine
+// and signing key of the access tokens.
+func (cfg Config) Validate() error {
+	if cfg.Company == "" {
+		return errors.New("company name is required")
+	}
+
+	for _, port := range []string{cfg.Port, cfg.CLIPort} {
+		if n, err := strconv.Atoi(port); err != nil || n <= 0 || n > 65535 {
+			return fmt.Errorf("invalid port %q", port)
+		}
+	}
+
+	if d, err := time.ParseDuration(cfg.MinConnection); err != nil || d < 0 {
+		return fmt.Errorf("invalid minimum connection time %q", cfg.MinConnection)
+	}
+
+	if cfg.ScheduleDays <= 0 {
+		return fmt.Errorf("invalid number of schedule days %d", cfg.ScheduleDays)
+	}
+
+	if models.NewPricingEngine(cfg.Pricing) == nil {
+		return fmt.Errorf("invalid pricing engine %q", cfg.Pricing)
+	}
+
+	if _, err := utils.NewTokenSigner(cfg.TokenAlg, cfg.TokenKey); err != nil {
+		return fmt.Errorf("invalid access token key: %w", err)
+	}
+
+	for _, ttl := range []string{cfg.AccessTTL, cfg.RefreshTTL} {
+		if d, err := time.ParseDuration(ttl); err != nil || d <= 0 {
+			return fmt.Errorf("invalid token lifetime %q", ttl)
+		}
+	}
+
+	if cfg.MaxDevices <= 0 {
+		return fmt.Errorf("invalid maximum number of devices %d", cfg.MaxDevices)
+	}
+
+	return nil
+}
+
+// MinConnectionTime returns the minimum connection time. The configuration must be valid.
+func (cfg Config) MinConnectionTime() time.Duration {
+	d, _ := time.ParseDuration(cfg.MinConnection)
+	return d
+}
+
+// AccessTokenTTL returns the lifetime of the access tokens. The configuration must be valid.
+func (cfg Config) AccessTokenTTL() time.Duration {
+	d, _ := time.ParseDuration(cfg.AccessTTL)
+	return d
+}
+
+// RefreshTokenTTL returns the idle time after which a refresh token expires. The configuration must be valid.
+func (cfg Config) RefreshTokenTTL() time.Duration {
+	d, _ := time.ParseDuration(cfg.RefreshTTL)
+	return d
+}
+
+func override(value *string, with string) {
+	if with != "" {
+		*value = with
+	}
+}
+
+func splitList(list string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
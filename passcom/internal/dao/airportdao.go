@@ -2,9 +2,9 @@
 package dao
 
 import (
-	"boreal/internal/models"
-	"boreal/internal/utils"
 	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
 )
 
 type DBAirportDAO struct{}
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+// DBAuditDAO persists the audit log of the actions taken on the server.
+type DBAuditDAO struct{}
+
+func (dao *DBAuditDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.AuditEntry{})
+}
+
+func (dao *DBAuditDAO) Insert(entry models.AuditEntry) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&entry).Error; err != nil {
+		log.Println("Error inserting audit entry:", err)
+		return err
+	}
+	return nil
+}
+
+// FindRecent returns the latest audit entries, from the newest to the oldest. A client ID other than 0 keeps
+// only the entries of the actions taken by the client or on its behalf.
+func (dao *DBAuditDAO) FindRecent(limit int, clientId uint) ([]models.AuditEntry, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	query := db.Order("id desc").Limit(limit)
+	if clientId != 0 {
+		query = query.Where("actor_id = ? OR client_id = ?", clientId, clientId)
+	}
+
+	var entries []models.AuditEntry = make([]models.AuditEntry, 0)
+	if err := query.Find(&entries).Error; err != nil {
+		log.Println("Error searching audit entries:", err)
+		return nil, err
+	}
+
+	return entries, nil
+}
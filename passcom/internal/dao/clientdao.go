@@ -1,9 +1,9 @@
 package dao
 
 import (
-	"boreal/internal/models"
-	"boreal/internal/utils"
 	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
 )
 
 // DBClientDAO is a data access object (DAO) for managing client data in memory.
@@ -46,7 +46,14 @@ func (dao *DBClientDAO) FindAll() []models.Client {
 	return clients
 }
 
-func (dao *DBClientDAO) Insert(client models.Client) {
+// Insert adds a new client to the database.
+//
+// Parameters:
+//   - client: The client model to be added.
+//
+// Return:
+//   - An error if the client was not created, like when its username is already taken.
+func (dao *DBClientDAO) Insert(client models.Client) error {
 	db, err := utils.OpenDb()
 
 	if err != nil {
@@ -54,7 +61,11 @@ func (dao *DBClientDAO) Insert(client models.Client) {
 	}
 	defer utils.CloseDb(db)
 
-	db.Create(&client)
+	if err := db.Create(&client).Error; err != nil {
+		log.Println("Client not created:", err)
+		return err
+	}
+	return nil
 }
 
 // Update updates an existing client in the memory data store.
@@ -92,15 +103,16 @@ func (dao *DBClientDAO) Update(c models.Client) error {
 
 }
 
-// Delete removes a client from the memory data store based on the provided client model.
+// Delete removes a client from the database based on the provided client model.
 //
-// The function checks if a client with the given Id exists in the data map.
-// If the client is found, the function deletes the client from the data map.
-// If the client is not found, the function does nothing.
+// The client is removed for good, not soft deleted, so its username can be taken by a new registration.
 //
 // Parameters:
-//   - t: The client model to be deleted. The function uses the client's Id field to identify the client in the data map.
-func (dao *DBClientDAO) Delete(client models.Client) {
+//   - client: The client model to be deleted. The function uses the client's Id field to identify the client.
+//
+// Return:
+//   - An error if the client was not deleted.
+func (dao *DBClientDAO) Delete(client models.Client) error {
 	db, err := utils.OpenDb()
 
 	if err != nil {
@@ -108,7 +120,11 @@ func (dao *DBClientDAO) Delete(client models.Client) {
 	}
 	defer utils.CloseDb(db)
 
-	db.Delete(client)
+	if err := db.Unscoped().Delete(&models.Client{}, "id = ?", client.ID).Error; err != nil {
+		log.Println("Client not deleted:", err)
+		return err
+	}
+	return nil
 }
 
 // FindById retrieves a client from the memory data store based on the provided UUID.
@@ -0,0 +1,162 @@
+package dao
+
+import (
+	"passcom/internal/dao/interfaces"
+	"passcom/internal/models"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var airportDao interfaces.AirportDAO
+var flightDao interfaces.FlightDAO
+var clientDao interfaces.ClientDAO
+var sessionDao interfaces.SessionDAO
+var ticketDao interfaces.TicketDAO
+var transactionDao interfaces.TransactionDAO
+var outboxDao interfaces.OutboxDAO
+var quotaDao interfaces.QuotaDAO
+var quotaSaleDao interfaces.QuotaSaleDAO
+var holdDao interfaces.HoldDAO
+var idempotencyDao interfaces.IdempotencyDAO
+var scheduleDao interfaces.ScheduleDAO
+var rebookingDao interfaces.RebookingDAO
+var refreshTokenDao interfaces.RefreshTokenDAO
+var auditDao interfaces.AuditDAO
+
+func GetFlightDAO() interfaces.FlightDAO {
+	if flightDao == nil {
+		flightDao = &DBFlightDAO{}
+		flightDao.New()
+	}
+
+	return flightDao
+}
+
+func GetClientDAO() interfaces.ClientDAO {
+	if clientDao == nil {
+		clientDao = &DBClientDAO{}
+		clientDao.New()
+	}
+
+	return clientDao
+}
+
+func GetSessionDAO() interfaces.SessionDAO {
+	if sessionDao == nil {
+		sessionDao = &MemorySessionDAO{
+			data: make(map[uuid.UUID]*models.Session),
+			mu:   sync.RWMutex{}}
+		sessionDao.New()
+	}
+
+	return sessionDao
+}
+
+func GetAirportDAO() interfaces.AirportDAO {
+	if airportDao == nil {
+		airportDao = &DBAirportDAO{}
+		airportDao.New()
+	}
+
+	return airportDao
+}
+
+func GetTicketDAO() interfaces.TicketDAO {
+	if ticketDao == nil {
+		ticketDao = &DBTicketDAO{}
+		ticketDao.New()
+	}
+
+	return ticketDao
+}
+
+func GetTransactionDAO() interfaces.TransactionDAO {
+	if transactionDao == nil {
+		transactionDao = &DBTransactionDAO{}
+		transactionDao.New()
+	}
+
+	return transactionDao
+}
+
+func GetOutboxDAO() interfaces.OutboxDAO {
+	if outboxDao == nil {
+		outboxDao = &DBOutboxDAO{}
+		outboxDao.New()
+	}
+
+	return outboxDao
+}
+
+func GetQuotaDAO() interfaces.QuotaDAO {
+	if quotaDao == nil {
+		quotaDao = &DBQuotaDAO{}
+		quotaDao.New()
+	}
+
+	return quotaDao
+}
+
+func GetQuotaSaleDAO() interfaces.QuotaSaleDAO {
+	if quotaSaleDao == nil {
+		quotaSaleDao = &DBQuotaSaleDAO{}
+		quotaSaleDao.New()
+	}
+
+	return quotaSaleDao
+}
+
+func GetHoldDAO() interfaces.HoldDAO {
+	if holdDao == nil {
+		holdDao = &DBHoldDAO{}
+		holdDao.New()
+	}
+
+	return holdDao
+}
+
+func GetIdempotencyDAO() interfaces.IdempotencyDAO {
+	if idempotencyDao == nil {
+		idempotencyDao = &DBIdempotencyDAO{}
+		idempotencyDao.New()
+	}
+
+	return idempotencyDao
+}
+
+func GetScheduleDAO() interfaces.ScheduleDAO {
+	if scheduleDao == nil {
+		scheduleDao = &DBScheduleDAO{}
+		scheduleDao.New()
+	}
+
+	return scheduleDao
+}
+
+func GetRebookingDAO() interfaces.RebookingDAO {
+	if rebookingDao == nil {
+		rebookingDao = &DBRebookingDAO{}
+		rebookingDao.New()
+	}
+
+	return rebookingDao
+}
+
+func GetRefreshTokenDAO() interfaces.RefreshTokenDAO {
+	if refreshTokenDao == nil {
+		refreshTokenDao = &DBRefreshTokenDAO{}
+		refreshTokenDao.New()
+	}
+
+	return refreshTokenDao
+}
+
+func GetAuditDAO() interfaces.AuditDAO {
+	if auditDao == nil {
+		auditDao = &DBAuditDAO{}
+		auditDao.New()
+	}
+
+	return auditDao
+}
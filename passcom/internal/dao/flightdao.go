@@ -0,0 +1,555 @@
+package dao
+
+import (
+	"container/heap"
+	"errors"
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+type DBFlightDAO struct {
+}
+
+func (dao *DBFlightDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.Flight{}, &models.Ticket{})
+
+	// Voos criados antes da capacidade ser registrada assumem que nenhum assento foi vendido
+	db.Model(&models.Flight{}).Where("capacity = 0 OR capacity IS NULL").Update("capacity", gorm.Expr("seats"))
+}
+
+func (dao *DBFlightDAO) FindAll() []models.Flight {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight = make([]models.Flight, 0)
+
+	db.Find(&flights)
+
+	return flights
+}
+
+// FindAllWithDeleted returns every flight, including the cancelled ones, kept as tombstones.
+func (dao *DBFlightDAO) FindAllWithDeleted() []models.Flight {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight = make([]models.Flight, 0)
+
+	db.Unscoped().Find(&flights)
+
+	return flights
+}
+
+func (dao *DBFlightDAO) Insert(flight models.Flight) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	db.Create(&flight)
+
+}
+
+func (dao *DBFlightDAO) Update(f models.Flight) error {
+
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	// Um voo cancelado continua sendo atualizado, para que a sua remoção seja gravada como as demais alterações
+	var flight models.Flight
+	if err := db.Unscoped().First(&flight, "id = ?", f.ID).Error; err != nil {
+		log.Println("Flight not found:", err)
+		return err
+	}
+
+	flight = f
+	if err := db.Unscoped().Save(&flight).Error; err != nil {
+		log.Println("Flight not updated:", err)
+		return err
+	}
+	log.Println("Flight updated:", flight)
+	return nil
+}
+
+func (dao *DBFlightDAO) Delete(a models.Flight) error {
+
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Delete(&models.Flight{}, "id = ?", a.ID).Error; err != nil {
+		log.Println("Error deleting Flight:", err)
+		return err
+	}
+
+	log.Println("Flight successfully deleted.")
+	return nil
+
+}
+
+func (dao *DBFlightDAO) FindById(id uint) (*models.Flight, error) {
+
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flight models.Flight
+
+	if err := db.Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Preload("Tickets").First(&flight, "id=?", id).Error; err != nil {
+		log.Println("Error searching flight:", err)
+		return nil, err
+	}
+	log.Println("Flight found:", flight)
+	return &flight, nil
+}
+
+func (dao *DBFlightDAO) FindBySource(id uint) ([]models.Flight, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight = make([]models.Flight, 0)
+	if err := db.Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Preload("Tickets").Where(&models.Flight{
+		OriginAirportID: id,
+	}).Find(&flights).Error; err != nil {
+		log.Println("Error searching flights:", err)
+		return nil, err
+	}
+	log.Println("Flights found:", flights)
+	return flights, nil
+}
+
+func (dao *DBFlightDAO) FindBySourceAndDest(source uint, dest uint) ([]models.Flight, error) {
+
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight = make([]models.Flight, 0)
+	if err := db.Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Preload("Tickets").
+		Where(&models.Flight{
+			OriginAirportID:      source,
+			DestinationAirportID: dest,
+		}).Find(&flights).Error; err != nil {
+		log.Println("Error searching flight:", err)
+		return nil, err
+	}
+	log.Println("Flights found:", flights)
+	return flights, nil
+}
+
+// FindShortestPaths finds up to search.K loop-free paths from source to dest over the flights of all companies,
+// ranked by total weight, using Yen's algorithm on top of a Dijkstra search. Flights without seats are not used.
+//
+// Parameters:
+//   - source: The ID of the origin airport.
+//   - dest: The ID of the destination airport.
+//   - search: The weight of each flight, the flights allowed, the connection rule and the limits of the search.
+//
+// Return:
+//   - The paths found, from the lowest to the highest total weight, each with its flights in order.
+//   - An error if there is no path from source to dest.
+func (dao *DBFlightDAO) FindShortestPaths(source uint, dest uint, search models.PathSearch) ([][]models.Flight, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight
+	if err := db.Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Find(&flights).Error; err != nil {
+		log.Println("Error loading flights:", err)
+		return nil, err
+	}
+
+	graph := make(map[uint][]models.Flight)
+	for _, flight := range flights {
+		if flight.Seats <= 0 || (search.Allow != nil && !search.Allow(flight)) {
+			continue
+		}
+		graph[flight.OriginAirportID] = append(graph[flight.OriginAirportID], flight)
+	}
+
+	first := shortestPath(graph, source, dest, search, nil, nil, nil)
+	if first == nil {
+		log.Println("No path found from source to destination")
+		return nil, errors.New("no path found from source to destination")
+	}
+
+	paths := [][]models.Flight{first}
+	candidates := make([][]models.Flight, 0)
+
+	for len(paths) < max(search.K, 1) {
+		previous := paths[len(paths)-1]
+
+		// Cada trecho do último caminho é trocado por um desvio a partir da sua origem
+		for i := range previous {
+			spur := previous[i].OriginAirportID
+			root := previous[:i]
+
+			// Remove os voos que levariam de novo a um caminho já encontrado com a mesma raiz
+			removedFlights := make(map[string]bool)
+			for _, path := range paths {
+				if len(path) > i && samePath(path[:i], root) {
+					removedFlights[path[i].UniqueId] = true
+				}
+			}
+
+			// Remove os aeroportos da raiz, para o desvio não formar ciclos
+			removedAirports := make(map[uint]bool)
+			for _, flight := range root {
+				removedAirports[flight.OriginAirportID] = true
+			}
+
+			// O desvio continua a partir do último voo da raiz, respeitando a conexão e o limite de trechos
+			var last *models.Flight
+			spurSearch := search
+			if i > 0 {
+				last = &root[i-1]
+				if search.MaxLegs > 0 {
+					spurSearch.MaxLegs = search.MaxLegs - i
+				}
+			}
+
+			detour := shortestPath(graph, spur, dest, spurSearch, last, removedFlights, removedAirports)
+			if detour == nil {
+				continue
+			}
+
+			candidate := append(append([]models.Flight{}, root...), detour...)
+			if !containsPath(paths, candidate) && !containsPath(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			wi, wj := pathWeight(candidates[i], search.Weight), pathWeight(candidates[j], search.Weight)
+			if wi != wj {
+				return wi < wj
+			}
+			return len(candidates[i]) < len(candidates[j])
+		})
+		paths = append(paths, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return paths, nil
+}
+
+// shortestPath runs Dijkstra from source to dest over graph, ignoring the removed flights and airports.
+// A search state is the last flight taken and the number of legs, since the next flight must connect to the
+// previous one and a more expensive path with fewer legs may still be the only one within search.MaxLegs.
+// The path continues after the flight last, or starts at source if last is nil. Returns nil if there is no path.
+func shortestPath(graph map[uint][]models.Flight, source uint, dest uint, search models.PathSearch, last *models.Flight, removedFlights map[string]bool, removedAirports map[uint]bool) []models.Flight {
+	type state struct {
+		flight string
+		legs   int
+	}
+	stateOf := func(path []string) state {
+		key := state{}
+		if len(path) > 0 {
+			key.flight = path[len(path)-1]
+		}
+		// Sem limite de trechos, basta o menor custo por voo
+		if search.MaxLegs > 0 {
+			key.legs = len(path)
+		}
+		return key
+	}
+
+	byUniqueId := make(map[string]models.Flight)
+	for _, flights := range graph {
+		for _, flight := range flights {
+			byUniqueId[flight.UniqueId] = flight
+		}
+	}
+
+	// Menor custo conhecido até cada estado
+	costs := map[state]uint{stateOf(nil): 0}
+	queue := &utils.PriorityQueue{{AirportID: source, Path: []string{}}}
+
+	for queue.Len() > 0 {
+		current := heap.Pop(queue).(*utils.Node)
+		legs := len(current.Path)
+
+		if current.Price > costs[stateOf(current.Path)] {
+			continue // Entrada desatualizada, o estado já foi alcançado por um caminho mais barato
+		}
+
+		if current.AirportID == dest {
+			path := make([]models.Flight, legs)
+			for i, uniqueId := range current.Path {
+				path[i] = byUniqueId[uniqueId]
+			}
+			return path
+		}
+
+		if search.MaxLegs > 0 && legs >= search.MaxLegs {
+			continue
+		}
+
+		previous := last
+		visited := map[uint]bool{source: true}
+		for _, uniqueId := range current.Path {
+			flight := byUniqueId[uniqueId]
+			visited[flight.DestinationAirportID] = true
+			previous = &flight
+		}
+
+		for _, flight := range graph[current.AirportID] {
+			next := flight.DestinationAirportID
+			if removedFlights[flight.UniqueId] || removedAirports[next] || visited[next] {
+				continue
+			}
+			if search.CanFollow != nil && !search.CanFollow(previous, flight) {
+				continue
+			}
+
+			newPath := append([]string{}, current.Path...)
+			newPath = append(newPath, flight.UniqueId)
+
+			cost := current.Price + search.Weight(previous, flight)
+			key := stateOf(newPath)
+			if known, ok := costs[key]; ok && known <= cost {
+				continue
+			}
+			costs[key] = cost
+
+			heap.Push(queue, &utils.Node{
+				AirportID: next,
+				Price:     cost,
+				Path:      newPath,
+			})
+		}
+	}
+
+	return nil
+}
+
+// pathWeight returns the total weight of a path, weighting each flight after the previous one.
+func pathWeight(path []models.Flight, weight func(*models.Flight, models.Flight) uint) uint {
+	var total uint
+	var previous *models.Flight
+	for i := range path {
+		total += weight(previous, path[i])
+		previous = &path[i]
+	}
+	return total
+}
+
+func samePath(a []models.Flight, b []models.Flight) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].UniqueId != b[i].UniqueId {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPath(paths [][]models.Flight, path []models.Flight) bool {
+	for _, p := range paths {
+		if samePath(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (dao *DBFlightDAO) FindByCompany(company string) ([]models.Flight, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight
+	if err := db.Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Preload("Tickets").
+		Where("company = ?", company).
+		Find(&flights).Error; err != nil {
+		log.Println("Error finding flights by company:", err)
+		return nil, err
+	}
+
+	log.Printf("Flights found for company %s: %v", company, flights)
+	return flights, nil
+}
+
+func (dao *DBFlightDAO) FindByUniqueId(uniqueId string) (*models.Flight, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flight models.Flight
+
+	// Busca o voo usando o campo `UniqueId`
+	if err := db.Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Preload("Tickets").
+		Where("unique_id = ?", uniqueId).
+		First(&flight).Error; err != nil {
+		log.Println("Error searching flight by unique ID:", err)
+		return nil, err
+	}
+
+	log.Println("Flight found by unique ID:", flight)
+	return &flight, nil
+}
+
+// FindByCompanyWithDeleted returns the flights of a company, including the cancelled ones.
+func (dao *DBFlightDAO) FindByCompanyWithDeleted(company string) ([]models.Flight, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flights []models.Flight
+	if err := db.Unscoped().
+		Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Where("company = ?", company).
+		Find(&flights).Error; err != nil {
+		log.Println("Error finding flights by company:", err)
+		return nil, err
+	}
+
+	return flights, nil
+}
+
+// FindByUniqueIdWithDeleted returns the flight with the given UniqueId, even if it was cancelled.
+func (dao *DBFlightDAO) FindByUniqueIdWithDeleted(uniqueId string) (*models.Flight, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var flight models.Flight
+	if err := db.Unscoped().
+		Preload("OriginAirport").
+		Preload("DestinationAirport").
+		Where("unique_id = ?", uniqueId).
+		First(&flight).Error; err != nil {
+		log.Println("Error searching flight by unique ID:", err)
+		return nil, err
+	}
+
+	return &flight, nil
+}
+
+// ExistsByUniqueId reports whether a flight with the given UniqueId was ever stored, even if it was deleted.
+func (dao *DBFlightDAO) ExistsByUniqueId(uniqueId string) bool {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var count int64
+	if err := db.Unscoped().Model(&models.Flight{}).Where("unique_id = ?", uniqueId).Count(&count).Error; err != nil {
+		log.Println("Error counting flights by unique ID:", err)
+		return false
+	}
+	return count > 0
+}
+
+func (dao *DBFlightDAO) DeleteByUniqueId(uniqueId string) error {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	// Exclui o voo com o `UniqueId` especificado
+	if err := db.Where("unique_id = ?", uniqueId).Delete(&models.Flight{}).Error; err != nil {
+		log.Println("Error deleting flight by unique ID:", err)
+		return err
+	}
+
+	log.Printf("Flight with unique ID %s successfully deleted.", uniqueId)
+	return nil
+}
+
+func (dao *DBFlightDAO) DeleteByCompany(company string) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Where("company =?", company).Delete(&models.Flight{}).Error; err != nil {
+		log.Println("Error deleting flights by company:", err)
+		return err
+	}
+
+	log.Printf("Flights deleted for company %s", company)
+	return nil
+}
+
+func (dao *DBFlightDAO) DeleteAll() {
+
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	db.Unscoped().Where("1=1").Delete(&models.Flight{})
+}
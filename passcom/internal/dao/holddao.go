@@ -0,0 +1,128 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"time"
+)
+
+// DBHoldDAO persists the seat holds, so that expired holds are released even after a restart.
+type DBHoldDAO struct{}
+
+func (dao *DBHoldDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.Hold{})
+}
+
+func (dao *DBHoldDAO) Insert(hold models.Hold) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&hold).Error; err != nil {
+		log.Println("Error inserting hold:", err)
+		return err
+	}
+
+	log.Println("Hold successfully inserted:", hold.HoldId)
+	return nil
+}
+
+func (dao *DBHoldDAO) Update(hold models.Hold) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Save(&hold).Error; err != nil {
+		log.Println("Hold not updated:", err)
+		return err
+	}
+	log.Println("Hold updated:", hold.HoldId)
+	return nil
+}
+
+// UpdateStatus changes the status of a hold only if it still has the given one, so that only one of
+// concurrent requests changes it.
+//
+// Return:
+//   - true if the status was changed, false if the hold doesn't have the given status.
+func (dao *DBHoldDAO) UpdateStatus(holdId string, from models.Status, to models.Status) (bool, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	result := db.Model(&models.Hold{}).Where("hold_id = ? AND status = ?", holdId, from).Update("status", to)
+	if result.Error != nil {
+		log.Println("Hold status not updated:", result.Error)
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (dao *DBHoldDAO) FindByHoldId(holdId string) (*models.Hold, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var hold models.Hold
+	if err := db.Where("hold_id = ?", holdId).First(&hold).Error; err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+func (dao *DBHoldDAO) FindByClient(clientId uint) ([]models.Hold, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var holds []models.Hold = make([]models.Hold, 0)
+	if err := db.Where("client_id = ?", clientId).Find(&holds).Error; err != nil {
+		log.Println("Error searching holds:", err)
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// FindExpired returns the holds still reserved whose time limit is before the given time.
+func (dao *DBHoldDAO) FindExpired(now time.Time) ([]models.Hold, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var holds []models.Hold = make([]models.Hold, 0)
+	if err := db.Where("status = ? AND expires_at < ?", models.PENDING, now).
+		Find(&holds).Error; err != nil {
+		log.Println("Error searching expired holds:", err)
+		return nil, err
+	}
+
+	return holds, nil
+}
@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// DBIdempotencyDAO persists the responses of deduplicated requests.
+type DBIdempotencyDAO struct{}
+
+func (dao *DBIdempotencyDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.IdempotencyRecord{})
+}
+
+// Insert stores a record, replacing an expired record with the same key not removed yet.
+func (dao *DBIdempotencyDAO) Insert(record models.IdempotencyRecord) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"request_hash", "status", "body", "expires_at", "updated_at"}),
+	}).Create(&record).Error; err != nil {
+		log.Println("Error inserting idempotency record:", err)
+		return err
+	}
+	return nil
+}
+
+// FindByKey returns the record with the given key, if it didn't expire before the given time.
+func (dao *DBIdempotencyDAO) FindByKey(key string, now time.Time) (*models.IdempotencyRecord, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var record models.IdempotencyRecord
+	if err := db.Where("key = ? AND expires_at > ?", key, now).
+		First(&record).Error; err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+func (dao *DBIdempotencyDAO) DeleteExpired(now time.Time) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Unscoped().Where("expires_at <= ?", now).
+		Delete(&models.IdempotencyRecord{}).Error; err != nil {
+		log.Println("Error deleting expired idempotency records:", err)
+		return err
+	}
+	return nil
+}
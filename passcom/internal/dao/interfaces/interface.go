@@ -0,0 +1,169 @@
+// Package dao implements the interfaces for the database of the server.
+package interfaces
+
+import (
+	"passcom/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FlightDAO interface {
+	FindAll() []models.Flight
+	Insert(models.Flight)
+	Update(models.Flight) error
+	Delete(models.Flight) error
+	FindById(uint) (*models.Flight, error)
+	FindBySource(uint) ([]models.Flight, error)
+	FindBySourceAndDest(uint, uint) ([]models.Flight, error)
+	FindByCompany(string) ([]models.Flight, error)
+	FindByUniqueId(string) (*models.Flight, error)
+	FindAllWithDeleted() []models.Flight
+	FindByCompanyWithDeleted(string) ([]models.Flight, error)
+	FindByUniqueIdWithDeleted(string) (*models.Flight, error)
+	ExistsByUniqueId(string) bool
+	FindShortestPaths(uint, uint, models.PathSearch) ([][]models.Flight, error)
+	DeleteByUniqueId(string) error
+	DeleteByCompany(string) error
+	DeleteAll()
+	New()
+}
+
+type ClientDAO interface {
+	FindAll() []models.Client
+	Insert(models.Client) error
+	Update(models.Client) error
+	Delete(models.Client) error
+	FindById(uint) (*models.Client, error)
+	FindByUsername(username string) (*models.Client, error)
+	New()
+}
+
+type SessionDAO interface {
+	FindAll() []*models.Session
+	Insert(*models.Session)
+	Update(*models.Session) error
+	Delete(*models.Session)
+	FindById(uuid.UUID) (*models.Session, error)
+	DeleteAll()
+	New()
+}
+
+type AirportDAO interface {
+	FindAll() []models.Airport
+	Insert(models.Airport)
+	Update(models.Airport) error
+	Delete(models.Airport)
+	FindById(uint) (*models.Airport, error)
+	New()
+	FindByName(name string) *models.Airport
+}
+
+type TicketDAO interface {
+	FindAll() []models.Ticket
+	Insert(models.Ticket)
+	InsertAll([]models.Ticket) error
+	Update(models.Ticket) error
+	Delete(models.Ticket)
+	FindById(uint) (*models.Ticket, error)
+	FindByUniqueId(string) (*models.Ticket, error)
+	FindByFlightId(uint) ([]models.Ticket, error)
+	FindPending() ([]models.Ticket, error)
+	DeleteByUniqueId(string) error
+	New()
+}
+
+type TransactionDAO interface {
+	FindAll() []models.Transaction
+	Insert(models.Transaction) error
+	Update(models.Transaction) error
+	FindByTransactionId(string) (*models.Transaction, error)
+	FindUnresolved() ([]models.Transaction, error)
+	FindByFlightId(string) ([]models.Transaction, error)
+	New()
+}
+
+type OutboxDAO interface {
+	Insert(models.OutboxMessage) (*models.OutboxMessage, error)
+	Update(models.OutboxMessage) error
+	Delete(models.OutboxMessage) error
+	FindByPeer(string) ([]models.OutboxMessage, error)
+	FindByPeerAndFlight(string, string) ([]models.OutboxMessage, error)
+	FindDue(time.Time) ([]models.OutboxMessage, error)
+	DeleteByPeer(string) error
+	New()
+}
+
+type QuotaDAO interface {
+	FindAll() []models.Quota
+	Insert(models.Quota) (*models.Quota, error)
+	Update(models.Quota) error
+	FindByHolder(string) ([]models.Quota, error)
+	FindByFlightAndHolder(string, string) (*models.Quota, error)
+	New()
+}
+
+type QuotaSaleDAO interface {
+	Insert(models.QuotaSale) error
+	Update(models.QuotaSale) error
+	FindByTicketId(string) (*models.QuotaSale, error)
+	FindUnacknowledged(string, string) ([]models.QuotaSale, error)
+	New()
+}
+
+type HoldDAO interface {
+	Insert(models.Hold) error
+	Update(models.Hold) error
+	UpdateStatus(string, models.Status, models.Status) (bool, error)
+	FindByHoldId(string) (*models.Hold, error)
+	FindByClient(uint) ([]models.Hold, error)
+	FindExpired(time.Time) ([]models.Hold, error)
+	New()
+}
+
+type IdempotencyDAO interface {
+	Insert(models.IdempotencyRecord) error
+	FindByKey(string, time.Time) (*models.IdempotencyRecord, error)
+	DeleteExpired(time.Time) error
+	New()
+}
+
+type ScheduleDAO interface {
+	Upsert(models.Schedule) error
+	FindByCompany(string) ([]models.Schedule, error)
+	New()
+}
+
+type RebookingDAO interface {
+	Insert(models.Rebooking) error
+	Update(models.Rebooking) error
+	FindById(uint) (*models.Rebooking, error)
+	FindByClient(uint) ([]models.Rebooking, error)
+	New()
+}
+
+type RefreshTokenDAO interface {
+	Insert(models.RefreshToken) error
+	Update(models.RefreshToken) error
+	Delete(models.RefreshToken) error
+	FindByTokenId(string) (*models.RefreshToken, error)
+	FindByClient(uint, time.Time) ([]models.RefreshToken, error)
+	DeleteExpired(time.Time) error
+	New()
+}
+
+type AuditDAO interface {
+	Insert(models.AuditEntry) error
+	FindRecent(limit int, clientId uint) ([]models.AuditEntry, error)
+	New()
+}
+
+type MessageDAO interface {
+	FindAll() []models.Message
+	Insert(models.Message)
+	Update(models.Message) error
+	Delete(models.Message)
+	FindById(uint) (*models.Message, error)
+	New()
+	FindByName(name string) *models.Message
+}
@@ -0,0 +1,136 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"time"
+)
+
+// DBOutboxDAO persists the flight broadcasts that were not delivered to the peers yet.
+type DBOutboxDAO struct{}
+
+func (dao *DBOutboxDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.OutboxMessage{})
+}
+
+func (dao *DBOutboxDAO) Insert(message models.OutboxMessage) (*models.OutboxMessage, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&message).Error; err != nil {
+		log.Println("Error inserting outbox message:", err)
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// Update saves the delivery attempts of a message. Messages removed in the meantime are not recreated.
+func (dao *DBOutboxDAO) Update(message models.OutboxMessage) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Model(&models.OutboxMessage{}).Where("id = ?", message.ID).
+		Updates(map[string]interface{}{
+			"attempts":     message.Attempts,
+			"next_attempt": message.NextAttempt,
+		}).Error; err != nil {
+		log.Println("Outbox message not updated:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBOutboxDAO) Delete(message models.OutboxMessage) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Unscoped().Delete(&models.OutboxMessage{}, "id = ?", message.ID).Error; err != nil {
+		log.Println("Error deleting outbox message:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBOutboxDAO) FindByPeer(peer string) ([]models.OutboxMessage, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var messages []models.OutboxMessage = make([]models.OutboxMessage, 0)
+	if err := db.Where("peer = ?", peer).Order("id").Find(&messages).Error; err != nil {
+		log.Println("Error searching outbox messages:", err)
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (dao *DBOutboxDAO) FindByPeerAndFlight(peer string, flightId string) ([]models.OutboxMessage, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var messages []models.OutboxMessage = make([]models.OutboxMessage, 0)
+	if err := db.Where("peer = ? AND flight_id = ?", peer, flightId).Find(&messages).Error; err != nil {
+		log.Println("Error searching outbox messages:", err)
+		return nil, err
+	}
+	return messages, nil
+}
+
+// FindDue returns the messages whose next delivery attempt is due at the given time.
+func (dao *DBOutboxDAO) FindDue(now time.Time) ([]models.OutboxMessage, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var messages []models.OutboxMessage = make([]models.OutboxMessage, 0)
+	if err := db.Where("next_attempt <= ?", now).Order("id").Find(&messages).Error; err != nil {
+		log.Println("Error searching outbox messages:", err)
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (dao *DBOutboxDAO) DeleteByPeer(peer string) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Unscoped().Where("peer = ?", peer).Delete(&models.OutboxMessage{}).Error; err != nil {
+		log.Println("Error deleting outbox messages:", err)
+		return err
+	}
+	return nil
+}
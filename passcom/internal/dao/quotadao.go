@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+// DBQuotaDAO persists the seat quotas held in escrow, both the ones granted by this server
+// and the ones it received from the owners of other flights.
+type DBQuotaDAO struct{}
+
+func (dao *DBQuotaDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.Quota{})
+}
+
+func (dao *DBQuotaDAO) FindAll() []models.Quota {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var quotas []models.Quota = make([]models.Quota, 0)
+
+	db.Find(&quotas)
+
+	return quotas
+}
+
+func (dao *DBQuotaDAO) Insert(quota models.Quota) (*models.Quota, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&quota).Error; err != nil {
+		log.Println("Error inserting quota:", err)
+		return nil, err
+	}
+
+	return &quota, nil
+}
+
+func (dao *DBQuotaDAO) Update(quota models.Quota) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Save(&quota).Error; err != nil {
+		log.Println("Quota not updated:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBQuotaDAO) FindByHolder(holder string) ([]models.Quota, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var quotas []models.Quota = make([]models.Quota, 0)
+	if err := db.Where("holder = ?", holder).Find(&quotas).Error; err != nil {
+		log.Println("Error searching quotas:", err)
+		return nil, err
+	}
+
+	return quotas, nil
+}
+
+func (dao *DBQuotaDAO) FindByFlightAndHolder(flightId string, holder string) (*models.Quota, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var quota models.Quota
+	if err := db.Where("flight_id = ? AND holder = ?", flightId, holder).
+		First(&quota).Error; err != nil {
+		return nil, err
+	}
+
+	return &quota, nil
+}
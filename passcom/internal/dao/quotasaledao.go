@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+// DBQuotaSaleDAO persists the seats sold by peers from the escrow quotas of the flights of this server,
+// with the seat and the price confirmed for each sale.
+type DBQuotaSaleDAO struct{}
+
+func (dao *DBQuotaSaleDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.QuotaSale{})
+}
+
+func (dao *DBQuotaSaleDAO) Insert(sale models.QuotaSale) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&sale).Error; err != nil {
+		log.Println("Error inserting quota sale:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBQuotaSaleDAO) Update(sale models.QuotaSale) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Save(&sale).Error; err != nil {
+		log.Println("Quota sale not updated:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBQuotaSaleDAO) FindByTicketId(ticketId string) (*models.QuotaSale, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var sale models.QuotaSale
+	if err := db.Where("ticket_id = ?", ticketId).First(&sale).Error; err != nil {
+		return nil, err
+	}
+
+	return &sale, nil
+}
+
+// FindUnacknowledged returns the confirmed sales of a flight by a holder not applied by it yet.
+func (dao *DBQuotaSaleDAO) FindUnacknowledged(flightId string, holder string) ([]models.QuotaSale, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var sales []models.QuotaSale = make([]models.QuotaSale, 0)
+	if err := db.Where("flight_id = ? AND holder = ? AND acknowledged = ? AND cancelled = ?", flightId, holder, false, false).
+		Find(&sales).Error; err != nil {
+		log.Println("Error searching quota sales:", err)
+		return nil, err
+	}
+
+	return sales, nil
+}
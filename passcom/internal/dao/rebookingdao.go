@@ -0,0 +1,86 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+// DBRebookingDAO persists the rebooking offers and refunds of the tickets of cancelled flights.
+type DBRebookingDAO struct{}
+
+func (dao *DBRebookingDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.Rebooking{})
+}
+
+func (dao *DBRebookingDAO) Insert(rebooking models.Rebooking) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&rebooking).Error; err != nil {
+		log.Println("Error inserting rebooking:", err)
+		return err
+	}
+
+	log.Println("Rebooking successfully inserted for ticket:", rebooking.TicketId)
+	return nil
+}
+
+func (dao *DBRebookingDAO) Update(rebooking models.Rebooking) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Save(&rebooking).Error; err != nil {
+		log.Println("Rebooking not updated:", err)
+		return err
+	}
+	log.Println("Rebooking updated:", rebooking.ID)
+	return nil
+}
+
+func (dao *DBRebookingDAO) FindById(id uint) (*models.Rebooking, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var rebooking models.Rebooking
+	if err := db.Take(&rebooking, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	return &rebooking, nil
+}
+
+func (dao *DBRebookingDAO) FindByClient(clientId uint) ([]models.Rebooking, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var rebookings []models.Rebooking = make([]models.Rebooking, 0)
+	if err := db.Where("client_id = ?", clientId).Find(&rebookings).Error; err != nil {
+		log.Println("Error searching rebookings:", err)
+		return nil, err
+	}
+
+	return rebookings, nil
+}
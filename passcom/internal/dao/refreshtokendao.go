@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"time"
+)
+
+// DBRefreshTokenDAO persists the refresh tokens, so the devices stay logged in after a restart.
+type DBRefreshTokenDAO struct{}
+
+func (dao *DBRefreshTokenDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.RefreshToken{})
+}
+
+func (dao *DBRefreshTokenDAO) Insert(token models.RefreshToken) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&token).Error; err != nil {
+		log.Println("Error inserting refresh token:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBRefreshTokenDAO) Update(token models.RefreshToken) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Save(&token).Error; err != nil {
+		log.Println("Refresh token not updated:", err)
+		return err
+	}
+	return nil
+}
+
+// Delete removes the refresh token for good, revoking the session of its device.
+func (dao *DBRefreshTokenDAO) Delete(token models.RefreshToken) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Unscoped().Delete(&models.RefreshToken{}, "id = ?", token.ID).Error; err != nil {
+		log.Println("Refresh token not deleted:", err)
+		return err
+	}
+	return nil
+}
+
+func (dao *DBRefreshTokenDAO) FindByTokenId(tokenId string) (*models.RefreshToken, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var token models.RefreshToken
+	if err := db.Where("token_id = ?", tokenId).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// FindByClient returns the refresh tokens of a client not yet expired at the given time,
+// from the least to the most recently used.
+func (dao *DBRefreshTokenDAO) FindByClient(clientId uint, now time.Time) ([]models.RefreshToken, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var tokens []models.RefreshToken = make([]models.RefreshToken, 0)
+	if err := db.Where("client_id = ? AND expires_at > ?", clientId, now).
+		Order("last_used_at").Find(&tokens).Error; err != nil {
+		log.Println("Error searching refresh tokens:", err)
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// DeleteExpired removes the refresh tokens expired before the given time.
+func (dao *DBRefreshTokenDAO) DeleteExpired(now time.Time) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Unscoped().Where("expires_at <= ?", now).Delete(&models.RefreshToken{}).Error; err != nil {
+		log.Println("Error deleting expired refresh tokens:", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// DBScheduleDAO persists the recurring schedules from which the dated flights are generated.
+type DBScheduleDAO struct{}
+
+func (dao *DBScheduleDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.Schedule{})
+}
+
+// Upsert stores a schedule, replacing the schedule with the same ScheduleId.
+func (dao *DBScheduleDAO) Upsert(schedule models.Schedule) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "schedule_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"company", "origin_airport_id", "destination_airport_id", "weekdays",
+			"departure_time", "duration", "origin_time_zone", "destination_time_zone", "seats", "price",
+			"start_date", "end_date", "updated_at", "deleted_at"}),
+	}).Create(&schedule).Error; err != nil {
+		log.Println("Error storing schedule:", err)
+		return err
+	}
+
+	log.Println("Schedule stored:", schedule.ScheduleId)
+	return nil
+}
+
+func (dao *DBScheduleDAO) FindByCompany(company string) ([]models.Schedule, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var schedules []models.Schedule = make([]models.Schedule, 0)
+	if err := db.Where("company = ?", company).Find(&schedules).Error; err != nil {
+		log.Println("Error finding schedules by company:", err)
+		return nil, err
+	}
+
+	return schedules, nil
+}
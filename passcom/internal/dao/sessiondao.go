@@ -2,7 +2,7 @@ package dao
 
 import (
 	"errors"
-	"rumos/internal/models"
+	"passcom/internal/models"
 	"sync"
 
 	"github.com/google/uuid"
@@ -42,7 +42,7 @@ func (dao *MemorySessionDAO) FindAll() []*models.Session {
 }
 
 // Insert adds a new session to the memory data store.
-// It generates a new UUID for the session, sets it as the ID, initializes the reservations map,
+// It generates a new UUID for the session when it has none, initializes the wishlist,
 // and then stores the session in the data map.
 //
 // Parameters:
@@ -51,11 +51,12 @@ func (dao *MemorySessionDAO) Insert(t *models.Session) {
 	dao.mu.Lock()
 	defer dao.mu.Unlock()
 
-	id := uuid.New()
-	t.ID = id
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
 	t.Mu = sync.RWMutex{}
 	t.Wishlist = make([]models.Flight, 0)
-	dao.data[id] = t
+	dao.data[t.ID] = t
 }
 
 // Update updates an existing session in the memory data store.
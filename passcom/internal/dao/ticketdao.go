@@ -1,9 +1,9 @@
 package dao
 
 import (
-	"giro/internal/models"
-	"giro/internal/utils"
 	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
 
 	"github.com/google/uuid"
 )
@@ -59,6 +59,34 @@ func (dao *DBTicketDAO) Insert(ticket models.Ticket) {
 	}
 }
 
+// InsertAll inserts every ticket in a single statement, so either all of them are created or none is.
+func (dao *DBTicketDAO) InsertAll(tickets []models.Ticket) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	for i := range tickets {
+		if tickets[i].UniqueId == "" {
+			uniqueId, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+			tickets[i].UniqueId = uniqueId.String()
+		}
+	}
+
+	if err := db.Create(&tickets).Error; err != nil {
+		log.Println("Error inserting tickets:", err)
+		return err
+	}
+
+	log.Println("Tickets successfully inserted:", len(tickets))
+	return nil
+}
+
 func (dao *DBTicketDAO) Update(a models.Ticket) error {
 
 	db, err := utils.OpenDb()
@@ -135,6 +163,40 @@ func (dao *DBTicketDAO) FindByUniqueId(uniqueId string) (*models.Ticket, error)
 	return &ticket, nil
 }
 
+// FindPending busca os tickets vendidos da cota em custódia que aguardam a confirmação do dono do voo.
+func (dao *DBTicketDAO) FindPending() ([]models.Ticket, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var tickets []models.Ticket = make([]models.Ticket, 0)
+	if err := db.Preload("Flight").Where("pending = ?", true).Find(&tickets).Error; err != nil {
+		log.Println("Error finding pending tickets:", err)
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// FindByFlightId busca os tickets de um voo pelo seu ID local.
+func (dao *DBTicketDAO) FindByFlightId(flightId uint) ([]models.Ticket, error) {
+	db, err := utils.OpenDb()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var tickets []models.Ticket = make([]models.Ticket, 0)
+	if err := db.Where("flight_id = ?", flightId).Find(&tickets).Error; err != nil {
+		log.Println("Error finding tickets by flight:", err)
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
 // DeleteByUniqueId remove um ticket pelo UniqueId.
 func (dao *DBTicketDAO) DeleteByUniqueId(uniqueId string) error {
 	db, err := utils.OpenDb()
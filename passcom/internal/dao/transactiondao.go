@@ -0,0 +1,134 @@
+package dao
+
+import (
+	"log"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+// DBTransactionDAO persists the state of distributed transactions, so that
+// coordinators and participants can recover in-doubt transactions after a restart.
+type DBTransactionDAO struct{}
+
+func (dao *DBTransactionDAO) New() {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+	db.AutoMigrate(&models.Transaction{})
+}
+
+func (dao *DBTransactionDAO) FindAll() []models.Transaction {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var transactions []models.Transaction = make([]models.Transaction, 0)
+
+	db.Find(&transactions)
+
+	return transactions
+}
+
+func (dao *DBTransactionDAO) Insert(transaction models.Transaction) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	if err := db.Create(&transaction).Error; err != nil {
+		log.Println("Error inserting transaction:", err)
+		return err
+	}
+
+	log.Println("Transaction successfully inserted:", transaction.TransactionId)
+	return nil
+}
+
+// Update saves the given transaction over the stored one with the same TransactionId.
+func (dao *DBTransactionDAO) Update(t models.Transaction) error {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var transaction models.Transaction
+	if err := db.First(&transaction, "transaction_id = ?", t.TransactionId).Error; err != nil {
+		log.Println("Transaction not found:", err)
+		return err
+	}
+
+	t.ID = transaction.ID
+	t.CreatedAt = transaction.CreatedAt
+	if err := db.Save(&t).Error; err != nil {
+		log.Println("Transaction not updated:", err)
+		return err
+	}
+	log.Println("Transaction updated:", t.TransactionId)
+	return nil
+}
+
+func (dao *DBTransactionDAO) FindByTransactionId(transactionId string) (*models.Transaction, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var transaction models.Transaction
+	if err := db.Where("transaction_id = ?", transactionId).
+		First(&transaction).Error; err != nil {
+		return nil, err
+	}
+
+	return &transaction, nil
+}
+
+// FindUnresolved returns the transactions that still require some action: the ones
+// without a final decision and the ones whose decision was not acknowledged yet.
+func (dao *DBTransactionDAO) FindUnresolved() ([]models.Transaction, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var transactions []models.Transaction = make([]models.Transaction, 0)
+	if err := db.Where("status = ? OR (role = ? AND acknowledged = ?)",
+		models.PENDING, models.RoleCoordinator, false).
+		Find(&transactions).Error; err != nil {
+		log.Println("Error searching unresolved transactions:", err)
+		return nil, err
+	}
+
+	return transactions, nil
+}
+
+// FindByFlightId returns the transactions of the flight with the given UniqueId.
+func (dao *DBTransactionDAO) FindByFlightId(flightId string) ([]models.Transaction, error) {
+	db, err := utils.OpenDb()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer utils.CloseDb(db)
+
+	var transactions []models.Transaction = make([]models.Transaction, 0)
+	if err := db.Where("flight_id = ?", flightId).Find(&transactions).Error; err != nil {
+		log.Println("Error searching transactions by flight:", err)
+		return nil, err
+	}
+
+	return transactions, nil
+}
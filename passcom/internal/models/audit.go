@@ -0,0 +1,20 @@
+package models
+
+import "gorm.io/gorm"
+
+// Atores das ações que não são executadas por um cliente
+const (
+	ActorAdminToken = "admin-token"
+	ActorCLI        = "cli"
+)
+
+// AuditEntry records an action taken on the server: who took it, on behalf of whom, and its result.
+type AuditEntry struct {
+	gorm.Model
+	ActorId  uint   // Cliente que executou a ação (0 quando não autenticado, no token administrativo e na CLI)
+	Role     string // Papel de quem executou a ação, ou admin-token e cli
+	ClientId uint   `gorm:"index"` // Cliente afetado pela ação; difere do ator quando um agente age em nome de um cliente
+	Method   string
+	Path     string // Caminho da requisição com os parâmetros, ou o comando da CLI
+	Status   int
+}
@@ -0,0 +1,17 @@
+package models
+
+type BuyTicket struct {
+	FlightId uint
+	Seat     string // Assento escolhido (opcional)
+	Class    string // Classe tarifária (opcional, a primeira classe do voo por padrão)
+}
+
+// SeatRequest is the body of the purchase and cancellation messages between servers:
+// the seat to take or to give back on a flight of the receiving server.
+type SeatRequest struct {
+	FlightId string // UniqueId do voo
+	Seat     string // Assento pedido ou atribuído (vazio para o primeiro livre)
+	Class    string // Classe tarifária do assento
+	Price    uint   // Preço do assento cotado pelo dono do voo
+	TicketId string // UniqueId do ticket vendido da cota em custódia ainda sem assento, cujo assento é o da venda
+}
@@ -0,0 +1,7 @@
+package models
+
+type BuyItinerary struct {
+	FlightIds []uint
+	Seats     []string // Assentos escolhidos para cada trecho, na ordem dos voos (opcional)
+	Classes   []string // Classes tarifárias de cada trecho, na ordem dos voos (opcional)
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+type Client struct {
+	gorm.Model
+	Name          string   `gorm:"size:100"`
+	Username      string   `gorm:"size:30;uniqueIndex"`
+	Password      string   `gorm:"size:128"` // Hash argon2id da senha (texto puro em bancos ainda não migrados)
+	HomeCompany   string   // Companhia de origem do cliente federado (vazio nos clientes da própria companhia)
+	Role          string   `gorm:"size:20;default:customer"` // customer, agent, airline-admin ou ops
+	ClientFlights []Ticket `gorm:"foreignKey:ClientId"`      // Relacionamento one-to-many com Ticket
+}
@@ -0,0 +1,17 @@
+package models
+
+type Connection struct {
+	Name     string
+	Address  string
+	Port     string
+	IsOnline bool
+	// Último valor do relógio da conexão gravado nos voos dela já sincronizados
+	SyncedClock int
+}
+
+// Heartbeat is the body of the reply to a heartbeat. Changes is the highest value of the clock entry of the
+// server stamped on one of its flights, which advances only when its flights change, unlike the clock itself.
+type Heartbeat struct {
+	Status  string
+	Changes int
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"slices"
+)
+
+// Fare is a fare class of a flight, with its own price, inventory of seats and refund rule.
+// The fare classes share the names of the cabins of the seat map (economy, premium and business).
+type Fare struct {
+	Class         string
+	Price         uint
+	Capacity      int
+	Seats         int // Assentos disponíveis da classe
+	RefundPercent int // Percentual do preço devolvido quando o cliente cancela o ticket
+}
+
+// Refund returns the amount given back when a ticket of the class bought for the given price is cancelled.
+func (f Fare) Refund(price uint) uint {
+	return price * uint(f.RefundPercent) / 100
+}
+
+// ValidateFares checks that the fare classes are known, not repeated and have valid prices, capacities and refund rules.
+func ValidateFares(fares []Fare) error {
+	if len(fares) == 0 {
+		return errors.New("fares without classes")
+	}
+	for i, fare := range fares {
+		switch fare.Class {
+		case CabinEconomy, CabinPremium, CabinBusiness:
+		default:
+			return errors.New("not valid fare class " + fare.Class)
+		}
+		if slices.ContainsFunc(fares[:i], func(other Fare) bool { return other.Class == fare.Class }) {
+			return errors.New("repeated fare class " + fare.Class)
+		}
+		if fare.Price == 0 || fare.Capacity <= 0 || fare.RefundPercent < 0 || fare.RefundPercent > 100 {
+			return errors.New("not valid fare " + fare.Class)
+		}
+	}
+	return nil
+}
+
+// FareClasses returns the fare classes of the flight. A flight without fare classes has a single economy class
+// made of its price and seats, refunded in full.
+func (f Flight) FareClasses() []Fare {
+	if len(f.Fares) == 0 {
+		return []Fare{{Class: CabinEconomy, Price: f.Price, Capacity: f.Capacity, Seats: f.Seats, RefundPercent: 100}}
+	}
+	return f.Fares
+}
+
+// Fare returns a fare class of the flight. An empty class is the first class of the flight, used by the sales
+// that don't choose one.
+func (f Flight) Fare(class string) (Fare, bool) {
+	fares := f.FareClasses()
+	if class == "" {
+		return fares[0], true
+	}
+
+	i := slices.IndexFunc(fares, func(fare Fare) bool { return fare.Class == class })
+	if i < 0 {
+		return Fare{}, false
+	}
+	return fares[i], true
+}
+
+// TakeSeats takes seats of a fare class from the flight.
+//
+// Return:
+//   - The fare class of the seats.
+//   - An error if the flight doesn't have the class or enough available seats in it.
+func (f *Flight) TakeSeats(class string, seats int) (Fare, error) {
+	fare, exists := f.Fare(class)
+	if !exists {
+		return fare, errors.New("fare class " + class + " not found")
+	}
+	if fare.Seats < seats {
+		return fare, errors.New("not available seats")
+	}
+
+	f.addSeats(fare.Class, -seats)
+	fare.Seats -= seats
+	return fare, nil
+}
+
+// ReturnSeats gives back seats of a fare class to the flight. Seats of an unknown class return to the first class.
+func (f *Flight) ReturnSeats(class string, seats int) {
+	if _, exists := f.Fare(class); !exists {
+		class = ""
+	}
+	fare, _ := f.Fare(class)
+	f.addSeats(fare.Class, seats)
+}
+
+// addSeats changes the available seats of a fare class and of the flight.
+func (f *Flight) addSeats(class string, seats int) {
+	f.Seats += seats
+	for i := range f.Fares {
+		if f.Fares[i].Class == class {
+			f.Fares[i].Seats += seats
+		}
+	}
+}
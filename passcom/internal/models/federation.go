@@ -0,0 +1,21 @@
+package models
+
+// CredentialCheck is the body sent by a server to the home company of a client to verify its credentials
+// in a federated login.
+type CredentialCheck struct {
+	Username string
+	Password string
+}
+
+// FederatedClient is the reply of the home company to a successful credential check.
+type FederatedClient struct {
+	Username string
+	Name     string
+}
+
+// FederatedTicketQuery is the body sent by the home company of a client to the other servers to list the
+// tickets the client bought on them through a federated login.
+type FederatedTicketQuery struct {
+	Username string
+	Home     string
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	FlightScheduled = "scheduled"
+	FlightDelayed   = "delayed"
+	FlightBoarding  = "boarding"
+	FlightDeparted  = "departed"
+	FlightArrived   = "arrived"
+	FlightCancelled = "cancelled"
+)
+
+type Flight struct {
+	gorm.Model
+	Company              string
+	UniqueId             string `gorm:"unique_id;unique"`
+	Price                uint
+	OriginAirportID      uint           `gorm:"not null"`
+	OriginAirport        Airport        `gorm:"foreignKey:OriginAirportID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	DestinationAirportID uint           `gorm:"not null"`
+	DestinationAirport   Airport        `gorm:"foreignKey:DestinationAirportID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Seats                int            // Assentos disponíveis
+	Capacity             int            // Total de assentos do voo, incluindo os vendidos, reservados e em custódia
+	Fares                []Fare         `gorm:"serializer:json"` // Classes tarifárias (vazio para uma única classe econômica)
+	Layout               Layout         `gorm:"serializer:json"` // Layout da aeronave (vazio para o layout padrão da capacidade)
+	Occupancy            []byte         // Mapa de bits dos assentos atribuídos, na ordem de Layout.Seats
+	Departure            time.Time      // Partida, com o fuso horário do aeroporto de origem (zero se o voo não tiver horário)
+	Arrival              time.Time      // Chegada, com o fuso horário do aeroporto de destino
+	Status               string         `gorm:"default:scheduled"` // Situação do voo, alterada somente pelo servidor dono
+	EstimatedDeparture   time.Time      // Partida estimada informada pelo dono (zero se não houver previsão)
+	Version              uint           // Incrementada pelo servidor dono a cada alteração do voo
+	VectorClock          map[string]int `gorm:"serializer:json"` // Relógio do servidor dono na última alteração do voo
+	Tickets              []Ticket       `gorm:"foreignKey:FlightId;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+}
+
+// Scheduled reports whether the flight has departure and arrival times.
+func (f Flight) Scheduled() bool {
+	return !f.Departure.IsZero() && !f.Arrival.IsZero()
+}
+
+// Duration returns the time between the departure and the arrival of the flight.
+func (f Flight) Duration() time.Duration {
+	return f.Arrival.Sub(f.Departure)
+}
+
+// Sold returns the number of seats taken from the flight by tickets, holds and escrow quotas.
+func (f Flight) Sold() int {
+	return f.Capacity - f.Seats
+}
+
+// ChangeStatus moves the flight to a new status, optionally with a new estimated departure.
+// A delayed flight must have an estimated departure. An arrived flight can't change anymore and a departed
+// flight can only arrive; cancellations remove the flight instead (see models.FlightCancelled).
+func (f *Flight) ChangeStatus(status string, estimatedDeparture *time.Time) error {
+	switch status {
+	case FlightScheduled, FlightDelayed, FlightBoarding, FlightDeparted, FlightArrived:
+	default:
+		return errors.New("not valid status")
+	}
+
+	if f.Status == FlightArrived || (f.Status == FlightDeparted && status != FlightArrived) {
+		return errors.New("flight already " + f.Status)
+	}
+
+	if estimatedDeparture != nil {
+		f.EstimatedDeparture = *estimatedDeparture
+	}
+	if status == FlightDelayed && f.EstimatedDeparture.IsZero() {
+		return errors.New("delayed flights require an estimated departure")
+	}
+
+	f.Status = status
+	return nil
+}
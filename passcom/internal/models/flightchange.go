@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// FlightChange is the body of the admin requests that create or edit a flight.
+// When editing, only the fields given are changed; the airports of a flight can't be changed.
+type FlightChange struct {
+	OriginAirportID      uint
+	DestinationAirportID uint
+	Price                *uint
+	Capacity             *int    // Total de assentos; não pode ser menor que os assentos já vendidos
+	Layout               *Layout // Layout da aeronave; sem capacidade, o voo usa todos os seus assentos
+	Fares                []Fare  // Classes tarifárias; substituem o preço e a capacidade do voo
+	Departure            *time.Time
+	Arrival              *time.Time
+}
+
+// FlightStatusChange is the body of the admin requests that change the status of a flight.
+type FlightStatusChange struct {
+	Status             string
+	EstimatedDeparture *time.Time // Opcional, exceto ao atrasar um voo sem previsão de partida
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Hold is a temporary reservation of seats of a flight, kept while the client finishes the checkout.
+// The seats are taken from the flight by its owner, which stores the hold as well and releases it
+// when it expires.
+type Hold struct {
+	gorm.Model
+	HoldId      string `gorm:"unique"`
+	FlightId    string // UniqueId do voo reservado
+	ClientId    uint   // Cliente que criou a reserva (somente no servidor do cliente)
+	Seats       int
+	Class       string // Classe tarifária dos assentos
+	Price       uint   // Preço de cada assento, cotado pelo dono do voo na reserva
+	Owner       string // ServerId do servidor dono do voo
+	Status      Status // PENDING enquanto reservada, COMMITED após o checkout e REJECTED após liberada
+	ExpiresAt   time.Time
+	SeatNumbers []string `gorm:"serializer:json"` // Assentos atribuídos pelo dono do voo no checkout
+}
+
+type HoldRequest struct {
+	FlightId uint
+	Seats    int
+	Class    string // Classe tarifária (opcional, a primeira classe do voo por padrão)
+	TTL      int    // Duração da reserva em segundos (opcional)
+}
+
+type Checkout struct {
+	HoldId string
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyRecord stores the final response of a request identified by an idempotency key or a message ID,
+// so that retries of the same request receive the stored response instead of being executed again.
+type IdempotencyRecord struct {
+	gorm.Model
+	Key         string `gorm:"unique"`
+	RequestHash string // Hash dos dados da requisição, para recusar a chave reutilizada em outra requisição
+	Status      int
+	Body        string
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+// FinalStatus reports whether a response status is a final outcome of a request, stored to be replayed:
+// a success or a client error that a retry would get again. Server errors and the client errors of conditions
+// that may change, such as a conflict with the current state of a flight, a purchase refused by an offline owner,
+// an aborted transaction or an empty quota (406 Not Acceptable) or an expired session, are not final.
+func FinalStatus(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusNotAcceptable, http.StatusRequestTimeout, http.StatusConflict,
+		http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
+	}
+	return (status >= 200 && status < 300) || (status >= 400 && status < 500)
+}
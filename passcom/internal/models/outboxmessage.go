@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OutboxMessage is a message waiting to be delivered to a peer: a flight broadcast or, when Path is set,
+// another server message, stored with its ID so the peer deduplicates the retries.
+type OutboxMessage struct {
+	gorm.Model
+	Peer        string `gorm:"index"` // ServerId da conexão de destino
+	FlightId    string `gorm:"index"` // UniqueId do voo transmitido (vazio para as demais mensagens)
+	Flight      string // Estado do voo serializado em JSON
+	Method      string // Método HTTP das demais mensagens
+	Path        string // Endpoint das demais mensagens (vazio para os broadcasts)
+	Message     string // Mensagem serializada em JSON, reenviada sempre com o mesmo ID
+	Attempts    int
+	NextAttempt time.Time
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Motores de preço aceitos pela configuração
+const (
+	PricingStatic     = "static"
+	PricingLoadFactor = "load-factor"
+)
+
+// PricingEngine computes the sale price of a seat of a fare class of a flight. It's only used by the owner
+// of the flight, which is the authority over its prices.
+type PricingEngine interface {
+	Price(flight Flight, fare Fare, now time.Time) uint
+}
+
+// StaticPricing sells every seat by the price of its fare class.
+type StaticPricing struct{}
+
+func (StaticPricing) Price(flight Flight, fare Fare, now time.Time) uint {
+	return fare.Price
+}
+
+// PriceStep is a percentage of the base price applied from a limit on.
+type PriceStep struct {
+	Limit   float64
+	Percent int
+}
+
+// LoadFactorPricing adjusts the price of a fare class by its load factor, the share of the seats of the class
+// already taken, and by the days left to the departure of the flight.
+type LoadFactorPricing struct {
+	LoadSteps []PriceStep // Vale o último passo cujo limite a taxa de ocupação atinge
+	DaySteps  []PriceStep // Vale o primeiro passo cujo limite os dias até a partida não ultrapassam
+}
+
+// DefaultLoadFactorPricing returns the load factor pricing used by default: up to 50% more as the class fills up,
+// and up to 30% more in the last days before the departure.
+func DefaultLoadFactorPricing() LoadFactorPricing {
+	return LoadFactorPricing{
+		LoadSteps: []PriceStep{{Limit: 0.5, Percent: 110}, {Limit: 0.75, Percent: 125}, {Limit: 0.9, Percent: 150}},
+		DaySteps:  []PriceStep{{Limit: 3, Percent: 130}, {Limit: 14, Percent: 115}},
+	}
+}
+
+// Price returns the price of the fare class for its current load factor. Flights without a schedule only
+// have the load factor adjustment.
+func (p LoadFactorPricing) Price(flight Flight, fare Fare, now time.Time) uint {
+	price := float64(fare.Price)
+
+	if fare.Capacity > 0 {
+		load := float64(fare.Capacity-fare.Seats) / float64(fare.Capacity)
+		percent := 100
+		for _, step := range p.LoadSteps {
+			if load >= step.Limit {
+				percent = step.Percent
+			}
+		}
+		price = price * float64(percent) / 100
+	}
+
+	if flight.Scheduled() {
+		days := flight.Departure.Sub(now).Hours() / 24
+		for _, step := range p.DaySteps {
+			if days <= step.Limit {
+				price = price * float64(step.Percent) / 100
+				break
+			}
+		}
+	}
+
+	return uint(math.Round(price))
+}
+
+// NewPricingEngine returns the pricing engine with the given name, or nil if it's unknown.
+func NewPricingEngine(name string) PricingEngine {
+	switch name {
+	case PricingStatic:
+		return StaticPricing{}
+	case PricingLoadFactor:
+		return DefaultLoadFactorPricing()
+	}
+	return nil
+}
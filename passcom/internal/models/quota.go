@@ -0,0 +1,36 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Quota records the seats of a flight held in escrow by a server. The owner of the flight keeps one row
+// per peer, with the last state reported by it, and the peer keeps its own row, holding itself.
+// All counters are cumulative, so the same state can be exchanged again without effect.
+type Quota struct {
+	gorm.Model
+	FlightId string `gorm:"index"` // UniqueId do voo
+	Holder   string `gorm:"index"` // ServerId do servidor que guarda a cota
+	Granted  int    // Assentos concedidos pelo dono do voo
+	Returned int    // Assentos devolvidos ao dono do voo
+	Sold     int    // Assentos vendidos a partir da cota
+}
+
+// Balance returns the seats of the quota still available for sale.
+func (q Quota) Balance() int {
+	return q.Granted - q.Returned - q.Sold
+}
+
+// QuotaSale records, on the owner of a flight, a seat sold by a peer from its escrow quota. The owner assigns
+// the seat and confirms the price of the sale, which are sent to the peer in the quota syncs until it applies them.
+type QuotaSale struct {
+	gorm.Model
+	FlightId     string `gorm:"index"`  // UniqueId do voo
+	Holder       string `gorm:"index"`  // ServerId do servidor que vendeu o assento
+	TicketId     string `gorm:"unique"` // UniqueId do ticket criado pelo servidor que vendeu
+	Class        string
+	Seat         string
+	Price        uint
+	Acknowledged bool // O servidor que vendeu aplicou o assento e o preço ao ticket
+	Cancelled    bool // O ticket foi cancelado; o assento atribuído volta ao voo
+}
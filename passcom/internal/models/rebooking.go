@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RebookingOffered  = "offered"
+	RebookingAccepted = "accepted"
+	RebookingRefunded = "refunded"
+)
+
+// Rebooking records what happened to a ticket whose flight was cancelled: an offer of the next itinerary
+// to the same destination, accepted or not by the client, or a refund when there is no itinerary or the
+// client declines the offer. It is kept by the server of the client, which removes the cancelled ticket.
+// The home company of a federated client keeps a copy, with the server where the ticket was bought.
+type Rebooking struct {
+	gorm.Model
+	ClientId             uint
+	TicketId             string // UniqueId do ticket cancelado
+	FlightId             string // UniqueId do voo cancelado
+	Company              string // Companhia do voo cancelado
+	OriginAirportID      uint
+	DestinationAirportID uint
+	Departure            time.Time
+	Class                string   // Classe tarifária do ticket cancelado, mantida na remarcação
+	Price                uint     // Preço pago pelo ticket cancelado
+	Itinerary            []string `gorm:"serializer:json"` // UniqueIds dos voos oferecidos, em ordem
+	Refund               uint     // Valor reembolsado, quando o ticket é reembolsado
+	Status               string
+	Server               string // Servidor em que o ticket foi comprado pelo login federado (vazio se foi neste)
+}
+
+type RebookingDecision struct {
+	Accept bool
+}
+
+// AffectedTickets is the body sent to the server of the clients of a cancelled flight, telling it which of their
+// tickets were affected. The owner of the flight sends it, with the cancelled flight, to the servers that bought seats
+// through the two-phase commit; a server that rebooked the tickets of federated clients sends it, with the offers and
+// refunds, to their home company.
+type AffectedTickets struct {
+	Flight     Flight
+	TicketIds  []string               // UniqueIds dos tickets afetados
+	Rebookings map[string][]Rebooking // Ofertas e reembolsos por nome de usuário na companhia de origem
+}
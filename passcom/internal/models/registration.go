@@ -0,0 +1,91 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+	MaxNameLength     = 100
+)
+
+// Nome de usuário com 3 a 30 letras minúsculas, dígitos, ponto, hífen ou sublinhado
+var usernamePattern = regexp.MustCompile(`^[a-z0-9._-]{3,30}$`)
+
+var (
+	ErrInvalidName     = errors.New("name must have between 1 and 100 characters")
+	ErrInvalidUsername = errors.New("username must have between 3 and 30 lowercase letters, digits, '.', '_' or '-'")
+	ErrInvalidPassword = errors.New("password must have between 8 and 72 characters, with letters and digits")
+)
+
+// Registration is the body of a self-service sign up.
+type Registration struct {
+	Name     string
+	Username string
+	Password string
+}
+
+// ProfileChange is the body of a profile update. Only the fields present are changed.
+type ProfileChange struct {
+	Name     *string
+	Username *string
+}
+
+// PasswordChange is the body of a password change, confirmed by the current password.
+type PasswordChange struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// AccountDeletion is the body of an account deletion, confirmed by the password.
+type AccountDeletion struct {
+	Password string
+}
+
+// Validate checks the name, username and password of the registration.
+func (r Registration) Validate() error {
+	if err := ValidateName(r.Name); err != nil {
+		return err
+	}
+	if err := ValidateUsername(r.Username); err != nil {
+		return err
+	}
+	return ValidatePassword(r.Password)
+}
+
+// ValidateName checks that the name isn't blank nor longer than the column of the client.
+func ValidateName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// ValidateUsername checks the length and the characters of the username.
+func ValidateUsername(username string) error {
+	if !usernamePattern.MatchString(username) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
+
+// ValidatePassword checks the length of the password and that it mixes letters and digits.
+func ValidatePassword(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < MinPasswordLength || length > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	if !strings.ContainsAny(password, "0123456789") || strings.IndexFunc(password, isLetter) < 0 {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+func isLetter(r rune) bool {
+	return strings.ToLower(string(r)) != strings.ToUpper(string(r))
+}
@@ -0,0 +1,27 @@
+package models
+
+// Codes of the errors returned to the clients, stable for the front ends to tell the errors apart
+// without parsing the message.
+const (
+	CodeNotAuthorized        = "NOT_AUTHORIZED"
+	CodeInvalidRequest       = "INVALID_REQUEST"
+	CodeInvalidName          = "INVALID_NAME"
+	CodeInvalidUsername      = "INVALID_USERNAME"
+	CodeInvalidPassword      = "INVALID_PASSWORD"
+	CodeUsernameTaken        = "USERNAME_TAKEN"
+	CodeWrongPassword        = "WRONG_PASSWORD"
+	CodeClientNotFound       = "CLIENT_NOT_FOUND"
+	CodeTicketsPending       = "TICKETS_PENDING"
+	CodeFederated            = "FEDERATED_ACCOUNT"
+	CodeForbidden            = "FORBIDDEN"
+	CodeInvalidRole          = "INVALID_ROLE"
+	CodeInternal             = "INTERNAL"
+	CodeIdempotencyKeyReused = "IDEMPOTENCY_KEY_REUSED"
+)
+
+type Response struct {
+	Error  string `json:"error"`
+	Code   string `json:"code,omitempty"` // Código do erro, vazio nas respostas de sucesso e nos erros antigos
+	Data   map[string]interface{}
+	Status int `json:"status"`
+}
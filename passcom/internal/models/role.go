@@ -0,0 +1,33 @@
+package models
+
+import "slices"
+
+// Papéis dos clientes, que definem os endpoints que cada um pode usar
+const (
+	RoleCustomer     = "customer"
+	RoleAgent        = "agent"
+	RoleAirlineAdmin = "airline-admin"
+	RoleOps          = "ops"
+)
+
+// Roles are all the roles a client may have.
+var Roles = []string{RoleCustomer, RoleAgent, RoleAirlineAdmin, RoleOps}
+
+// ValidRole tells if the role is one of the known roles.
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// EffectiveRole returns the role of the client. Clients created before the roles are customers.
+func (c Client) EffectiveRole() string {
+	if c.Role == "" {
+		return RoleCustomer
+	}
+	return c.Role
+}
+
+// RoleChange is the body of a change of the role of a client by an airline admin.
+type RoleChange struct {
+	Username string
+	Role     string
+}
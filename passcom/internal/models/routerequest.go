@@ -0,0 +1,34 @@
+package models
+
+// Critérios aceitos pelo parâmetro mode de /route
+const (
+	RouteFewestHops = "fewest-hops"
+	RouteCheapest   = "cheapest"
+	RouteFastest    = "fastest"
+)
+
+type RouteRequest struct {
+	Source    string
+	Dest      string
+	Mode      string   // Vazio mantém a resposta antiga: voos diretos seguidos do caminho encontrado por BFS
+	K         int      // Quantidade máxima de itinerários alternativos
+	MaxLegs   int      // Quantidade máxima de trechos de cada itinerário (0 não limita)
+	Companies []string // Companhias permitidas (vazio permite todas)
+	Exclude   []string // Companhias excluídas
+	Date      string   // Data de partida do primeiro trecho (AAAA-MM-DD), vazia para qualquer data
+	Class     string   // Classe tarifária com assentos em todos os trechos (vazia para a primeira classe de cada voo)
+}
+
+// Ranked reports whether the request asks for ranked itineraries instead of the BFS path.
+func (r RouteRequest) Ranked() bool {
+	return r.Mode != "" || r.K > 0 || r.MaxLegs > 0 || len(r.Companies) > 0 || len(r.Exclude) > 0 || r.Class != ""
+}
+
+// PathSearch holds the rules of an itinerary search over the flights of all companies.
+type PathSearch struct {
+	K         int                                        // Quantidade máxima de itinerários (0 equivale a 1)
+	MaxLegs   int                                        // Quantidade máxima de trechos (0 não limita)
+	Weight    func(previous *Flight, flight Flight) uint // Custo de tomar flight depois de previous (nil no primeiro trecho)
+	Allow     func(flight Flight) bool                   // Voos que podem ser usados (nil permite todos)
+	CanFollow func(previous *Flight, flight Flight) bool // Se flight pode ser tomado depois de previous (nil no primeiro trecho; nil permite todos)
+}
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Schedule is a recurring flight of a company, such as "rumos 3→4 every Mon/Wed/Fri at 08:00".
+// The generator materialises it into dated flights whose UniqueId is derived from the schedule and the date,
+// so generating the same day twice, on any server, yields the same flight.
+type Schedule struct {
+	gorm.Model
+	ScheduleId           string `gorm:"unique"`
+	Company              string
+	OriginAirportID      uint
+	DestinationAirportID uint
+	Weekdays             []string `gorm:"serializer:json"` // Dias da semana em que o voo parte (Mon, Tue, Wed, Thu, Fri, Sat, Sun)
+	DepartureTime        string   // Horário de partida (HH:MM) no fuso do aeroporto de origem
+	Duration             string   // Duração do voo (ex.: 2h30m)
+	OriginTimeZone       string   // Fuso do aeroporto de origem (ex.: America/Manaus)
+	DestinationTimeZone  string   // Fuso do aeroporto de destino
+	Seats                int
+	Price                uint
+	StartDate            string // Primeiro dia do período de operação (AAAA-MM-DD)
+	EndDate              string // Último dia do período de operação (AAAA-MM-DD), vazio se não houver
+}
+
+var weekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// Validate checks that the schedule identifies its route and has valid days, times, durations and time zones.
+func (s Schedule) Validate() error {
+	if s.ScheduleId == "" || s.Company == "" {
+		return errors.New("schedule id and company are required")
+	}
+	if s.OriginAirportID == 0 || s.DestinationAirportID == 0 || s.OriginAirportID == s.DestinationAirportID {
+		return fmt.Errorf("schedule %s: invalid airports", s.ScheduleId)
+	}
+	if len(s.Weekdays) == 0 {
+		return fmt.Errorf("schedule %s: no weekdays", s.ScheduleId)
+	}
+	for _, day := range s.Weekdays {
+		if !slices.Contains(weekdays, day) {
+			return fmt.Errorf("schedule %s: invalid weekday %q", s.ScheduleId, day)
+		}
+	}
+	if _, err := time.Parse("15:04", s.DepartureTime); err != nil {
+		return fmt.Errorf("schedule %s: invalid departure time %q", s.ScheduleId, s.DepartureTime)
+	}
+	if d, err := time.ParseDuration(s.Duration); err != nil || d <= 0 {
+		return fmt.Errorf("schedule %s: invalid duration %q", s.ScheduleId, s.Duration)
+	}
+	for _, zone := range []string{s.OriginTimeZone, s.DestinationTimeZone} {
+		if _, err := time.LoadLocation(zone); err != nil || zone == "" {
+			return fmt.Errorf("schedule %s: invalid time zone %q", s.ScheduleId, zone)
+		}
+	}
+	if _, err := time.Parse(time.DateOnly, s.StartDate); err != nil {
+		return fmt.Errorf("schedule %s: invalid start date %q", s.ScheduleId, s.StartDate)
+	}
+	if s.EndDate != "" {
+		if _, err := time.Parse(time.DateOnly, s.EndDate); err != nil {
+			return fmt.Errorf("schedule %s: invalid end date %q", s.ScheduleId, s.EndDate)
+		}
+		if s.EndDate < s.StartDate {
+			return fmt.Errorf("schedule %s: end date before start date", s.ScheduleId)
+		}
+	}
+	if s.Seats <= 0 {
+		return fmt.Errorf("schedule %s: invalid seats %d", s.ScheduleId, s.Seats)
+	}
+	return nil
+}
+
+// FlightsBetween materialises the flights of the schedule that depart within [from, until).
+// The schedule must be valid.
+func (s Schedule) FlightsBetween(from time.Time, until time.Time) []Flight {
+	origin, _ := time.LoadLocation(s.OriginTimeZone)
+	destination, _ := time.LoadLocation(s.DestinationTimeZone)
+	clock, _ := time.Parse("15:04", s.DepartureTime)
+	duration, _ := time.ParseDuration(s.Duration)
+
+	flights := make([]Flight, 0)
+
+	// Percorre os dias no fuso de origem, do dia de from até o dia de until
+	start := from.In(origin)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, origin); day.Before(until); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		if date < s.StartDate || (s.EndDate != "" && date > s.EndDate) {
+			continue
+		}
+		if !slices.Contains(s.Weekdays, weekdays[day.Weekday()]) {
+			continue
+		}
+
+		departure := time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, origin)
+		if departure.Before(from) || !departure.Before(until) {
+			continue
+		}
+
+		flights = append(flights, Flight{
+			Company:              s.Company,
+			UniqueId:             s.FlightUniqueId(date),
+			Price:                s.Price,
+			OriginAirportID:      s.OriginAirportID,
+			DestinationAirportID: s.DestinationAirportID,
+			Seats:                s.Seats,
+			Capacity:             s.Seats,
+			Departure:            departure,
+			Arrival:              departure.Add(duration).In(destination),
+		})
+	}
+
+	return flights
+}
+
+// FlightUniqueId returns the UniqueId of the flight of the schedule that departs on the given date (AAAA-MM-DD).
+func (s Schedule) FlightUniqueId(date string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte("passcom/"+s.Company+"/"+s.ScheduleId+"/"+date)).String()
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	CabinEconomy  = "economy"
+	CabinPremium  = "premium"
+	CabinBusiness = "business"
+)
+
+// Cabin is a section of an aircraft whose rows have the same seat letters.
+type Cabin struct {
+	Name    string // economy, premium ou business
+	Rows    int
+	Letters string // Letras dos assentos de cada fileira (ex.: ABCDEF)
+}
+
+// Layout is the seat layout of an aircraft: its cabins from the front to the back.
+// The rows are numbered from 1 across all cabins.
+type Layout []Cabin
+
+// Seat is a seat of the seat map of a flight.
+type Seat struct {
+	Number string // Fileira e letra (ex.: 12C)
+	Cabin  string
+	Taken  bool
+}
+
+// DefaultLayout returns the layout used by flights without one: a single economy cabin with six seats per row
+// and enough rows for the capacity.
+func DefaultLayout(capacity int) Layout {
+	return Layout{{Name: CabinEconomy, Rows: (capacity + 5) / 6, Letters: "ABCDEF"}}
+}
+
+// Validate checks that every cabin has a known name, rows and distinct seat letters.
+func (l Layout) Validate() error {
+	if len(l) == 0 {
+		return errors.New("layout without cabins")
+	}
+	for _, cabin := range l {
+		switch cabin.Name {
+		case CabinEconomy, CabinPremium, CabinBusiness:
+		default:
+			return errors.New("not valid cabin " + cabin.Name)
+		}
+		if cabin.Rows <= 0 || cabin.Letters == "" {
+			return errors.New("cabin " + cabin.Name + " without seats")
+		}
+		for i, letter := range cabin.Letters {
+			if letter < 'A' || letter > 'Z' || strings.ContainsRune(cabin.Letters[i+1:], letter) {
+				return errors.New("not valid seat letters " + cabin.Letters)
+			}
+		}
+	}
+	return nil
+}
+
+// Count returns the number of seats of the layout.
+func (l Layout) Count() int {
+	count := 0
+	for _, cabin := range l {
+		count += cabin.Rows * len(cabin.Letters)
+	}
+	return count
+}
+
+// Seats lists the seats of the layout in order: by row, and by letter within each row.
+// The position of a seat in this list is its bit in the occupancy bitmap of a flight.
+func (l Layout) Seats() []Seat {
+	seats := make([]Seat, 0, l.Count())
+	row := 1
+	for _, cabin := range l {
+		for i := 0; i < cabin.Rows; i++ {
+			for _, letter := range cabin.Letters {
+				seats = append(seats, Seat{Number: strconv.Itoa(row) + string(letter), Cabin: cabin.Name})
+			}
+			row++
+		}
+	}
+	return seats
+}
+
+// SeatLayout returns the layout of the flight, or the default layout for its capacity if it has none.
+func (f Flight) SeatLayout() Layout {
+	if len(f.Layout) == 0 {
+		return DefaultLayout(f.Capacity)
+	}
+	return f.Layout
+}
+
+// SeatMap returns the seats of the flight with their occupancy. Only the first Capacity seats of the layout are sold.
+func (f Flight) SeatMap() []Seat {
+	seats := f.SeatLayout().Seats()
+	if len(seats) > f.Capacity {
+		seats = seats[:max(f.Capacity, 0)]
+	}
+	for i := range seats {
+		seats[i].Taken = f.seatTaken(i)
+	}
+	return seats
+}
+
+// OccupiedSeats returns the number of seats assigned to tickets. It may be lower than the seats sold,
+// since the seats sold from escrow quotas get a seat only when the owner confirms the sale.
+func (f Flight) OccupiedSeats() int {
+	count := 0
+	for _, b := range f.Occupancy {
+		count += bits.OnesCount8(b)
+	}
+	return count
+}
+
+// AssignSeat marks a seat of the flight as taken. Without a seat number, the first free seat is assigned.
+// When the layout has a cabin named after the fare class, only the seats of that cabin can be assigned.
+// It doesn't change the available seats of the flight, which are taken by the caller.
+//
+// Return:
+//   - The number of the assigned seat.
+//   - An error if the seat doesn't exist, is already taken or is outside the cabin of the class.
+func (f *Flight) AssignSeat(number, class string) (string, error) {
+	cabin := slices.ContainsFunc(f.SeatLayout(), func(c Cabin) bool { return c.Name == class })
+
+	for i, seat := range f.SeatMap() {
+		if number != "" && seat.Number != number {
+			continue
+		}
+		if cabin && seat.Cabin != class {
+			if number == "" {
+				continue
+			}
+			return "", errors.New("seat " + number + " is not in the " + class + " cabin")
+		}
+		if seat.Taken {
+			if number == "" {
+				continue
+			}
+			return "", errors.New("seat " + number + " is taken")
+		}
+		f.setSeat(i, true)
+		return seat.Number, nil
+	}
+
+	if number == "" {
+		return "", errors.New("no free seats")
+	}
+	return "", errors.New("seat " + number + " not found")
+}
+
+// ReleaseSeat marks a seat of the flight as free. Unknown seats are ignored.
+func (f *Flight) ReleaseSeat(number string) {
+	for i, seat := range f.SeatMap() {
+		if seat.Number == number {
+			f.setSeat(i, false)
+			return
+		}
+	}
+}
+
+func (f Flight) seatTaken(i int) bool {
+	return i/8 < len(f.Occupancy) && f.Occupancy[i/8]&(1<<(i%8)) != 0
+}
+
+func (f *Flight) setSeat(i int, taken bool) {
+	for len(f.Occupancy) <= i/8 {
+		f.Occupancy = append(f.Occupancy, 0)
+	}
+	if taken {
+		f.Occupancy[i/8] |= 1 << (i % 8)
+	} else {
+		f.Occupancy[i/8] &^= 1 << (i % 8)
+	}
+}
@@ -10,6 +10,9 @@ import (
 type Session struct {
 	ID             uuid.UUID
 	ClientID       uint
+	HomeCompany    string // Companhia de origem do cliente federado (vazio nos clientes locais)
+	Role           string
+	ActorID        uint // Agente que age em nome do cliente, quando houver
 	LastTimeActive time.Time
 	Mu             sync.RWMutex
 	Wishlist       []Flight
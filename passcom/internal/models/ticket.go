@@ -9,6 +9,10 @@ type Ticket struct {
 	ClientId uint   `gorm:"not null;constraint:OnDelete:CASCADE"` // Chave estrangeira para Client
 	FlightId uint   `gorm:"not null;constraint:OnDelete:CASCADE"` // Chave estrangeira para Flight
 	UniqueId string `gorm:"unique_id;unique"`
+	Seat     string // Número do assento atribuído pelo dono do voo (vazio na venda da cota em custódia ainda pendente)
+	Class    string // Classe tarifária
+	Price    uint   // Preço pago, cotado pelo dono do voo na venda ou na confirmação da venda da cota
+	Pending  bool   // Vendido da cota em custódia, aguardando o dono do voo atribuir o assento e confirmar o preço
 
 	Client Client `gorm:"foreignKey:ClientId;references:ID"` // Relacionamento many-to-one com Client
 	Flight Flight `gorm:"foreignKey:FlightId;references:ID"` // Relacionamento many-to-one com Flight
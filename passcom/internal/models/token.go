@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AccessClaims are the claims of a signed access token. The token is validated by its signature and
+// expiration alone, without looking up the session.
+type AccessClaims struct {
+	Subject   uint   `json:"sub"`            // ID do cliente
+	Session   string `json:"sid"`            // TokenId do refresh token do dispositivo
+	Issuer    string `json:"iss"`            // Companhia que emitiu o token
+	Home      string `json:"home,omitempty"` // Companhia de origem do cliente, no login federado
+	Role      string `json:"role"`
+	Actor     uint   `json:"act,omitempty"` // Agente que age em nome do cliente
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Expired tells if the token is no longer valid at the given time.
+func (c AccessClaims) Expired(now time.Time) bool {
+	return now.Unix() >= c.ExpiresAt
+}
+
+// RefreshToken is the stored session of a device of a client, used to get new access tokens. Only the hash
+// of its secret is stored, and the secret changes every time the token is used. Removing it logs the device out.
+type RefreshToken struct {
+	gorm.Model
+	TokenId    string `gorm:"uniqueIndex"` // Identifica o dispositivo, também presente nos tokens de acesso (sid)
+	ClientId   uint   `gorm:"index"`
+	SecretHash string // SHA-256 do segredo atual
+	Device     string // Descrição do dispositivo (User-Agent, por padrão)
+	Home       string // Companhia de origem do cliente, no login federado
+	LastUsedAt time.Time
+	ExpiresAt  time.Time
+}
+
+// RefreshRequest is the body of a request for new tokens.
+type RefreshRequest struct {
+	Refresh string `json:"refresh"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+const (
+	TypePurchase = "purchase"
+	TypeCancel   = "cancel"
+)
+
+const (
+	RoleCoordinator = "coordinator"
+	RoleParticipant = "participant"
+)
+
+type Transaction struct {
+	gorm.Model
+	TransactionId string `gorm:"unique"`
+	ItineraryId   string // Agrupa as transações das pernas de um mesmo itinerário
+	Type          string
+	FlightId      string // UniqueId do voo envolvido na transação
+	Seat          string // Assento pedido pelo coordenador e atribuído pelo participante ao votar
+	Class         string // Classe tarifária do assento
+	Price         uint   // Preço do assento cotado pelo participante ao votar
+	ClientId      uint   // Cliente local que originou a compra (somente no coordenador)
+	Role          string
+	Coordinator   string // ServerId do servidor coordenador
+	Participant   string // ServerId do servidor dono do voo
+	Status        Status
+	Prepared      bool // Indica se o participante votou pela compra, reservando o assento
+	Acknowledged  bool // Indica se o participante confirmou a decisão final
+}
@@ -0,0 +1,312 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	ON_BEHALF_HEADER = "On-Behalf-Of"
+	ON_BEHALF_TTL    = time.Minute
+	AUDIT_LIMIT      = 100
+)
+
+// permission tells who may use an endpoint: the roles of the clients allowed, whether agents may act on
+// behalf of customers and whether the admin token is accepted. An endpoint without roles is public.
+type permission struct {
+	roles      []string
+	onBehalf   bool
+	adminToken bool
+}
+
+var (
+	public    = permission{}
+	everyone  = permission{roles: models.Roles}
+	shoppers  = permission{roles: []string{models.RoleCustomer, models.RoleAgent}}
+	bookings  = permission{roles: []string{models.RoleCustomer, models.RoleAgent}, onBehalf: true}
+	admins    = permission{roles: []string{models.RoleAirlineAdmin}, adminToken: true}
+	operators = permission{roles: []string{models.RoleAirlineAdmin, models.RoleOps}, adminToken: true}
+)
+
+// statusRecorder keeps the status written by a handler, so the action can be audited with its result.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// authorize wraps the handler of an endpoint to enforce its permission. The role is read from the access
+// token, so no lookup is made to authorize a request. An agent acting on behalf of a customer, given by its
+// username in the On-Behalf-Of header, has the request handled with a short-lived token of the customer
+// signed with the agent as actor. Every request, reads and the ones refused by the permission included, is
+// recorded in the audit log with the acting client, the client affected and the resulting status.
+//
+// Parameters:
+//   - handler: The handler of the endpoint.
+//   - p: The permission of the endpoint.
+//
+// Return:
+//   - The handler that enforces the permission before calling the given one.
+func authorize(handler http.HandlerFunc, p permission) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		allowCrossOrigin(w, r)
+		if r.Method == http.MethodOptions {
+			return
+		}
+
+		entry := models.AuditEntry{Method: r.Method, Path: r.URL.RequestURI()}
+		recorder := &statusRecorder{ResponseWriter: w}
+		// As requisições recusadas pela permissão do endpoint também são registradas
+		defer func() {
+			entry.Status = recorder.status
+			dao.GetAuditDAO().Insert(entry)
+		}()
+
+		if p.adminToken && isAdminToken(r) {
+			entry.Role = models.ActorAdminToken
+		} else if len(p.roles) > 0 {
+			session, exists := SessionIfExists(r.Header.Get("Authorization"))
+			if !exists {
+				returnResponse(recorder, r, notAuthorized())
+				return
+			}
+
+			entry.ActorId, entry.Role, entry.ClientId = session.ClientID, session.Role, session.ClientID
+			if !slices.Contains(p.roles, session.Role) {
+				returnResponse(recorder, r, forbidden("role "+session.Role+" not allowed"))
+				return
+			}
+
+			if username := r.Header.Get(ON_BEHALF_HEADER); username != "" {
+				customer, failure := actOnBehalf(r, session, username, p)
+				if failure != nil {
+					returnResponse(recorder, r, *failure)
+					return
+				}
+				entry.ClientId = customer.ID
+			}
+		}
+
+		handler(recorder, r)
+	}
+}
+
+// actOnBehalf replaces the access token of an agent in the request by a short-lived token of the customer,
+// keeping the agent as actor, so the handlers act on the customer as if it had made the request.
+//
+// Return:
+//   - The customer.
+//   - nil, or the response to be returned when the endpoint doesn't allow it, the client is not an agent or
+//     the customer is not a customer of this company.
+func actOnBehalf(r *http.Request, session *models.Session, username string, p permission) (*models.Client, *models.Response) {
+	if !p.onBehalf || session.Role != models.RoleAgent {
+		failure := forbidden("acting on behalf of customers not allowed")
+		return nil, &failure
+	}
+
+	customer, err := dao.GetClientDAO().FindByUsername(username)
+	if err != nil || customer.HomeCompany != "" || customer.EffectiveRole() != models.RoleCustomer {
+		return nil, &models.Response{
+			Error:  "customer not found",
+			Code:   models.CodeClientNotFound,
+			Status: http.StatusNotFound,
+		}
+	}
+
+	now := time.Now()
+	token, err := signer.Sign(models.AccessClaims{
+		Subject:   customer.ID,
+		Session:   session.ID.String(),
+		Issuer:    cfg.Company,
+		Role:      models.RoleCustomer,
+		Actor:     session.ClientID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ON_BEHALF_TTL).Unix(),
+	})
+	if err != nil {
+		failure := internalError("failed to create token")
+		return nil, &failure
+	}
+
+	r.Header.Set("Authorization", token)
+	return customer, nil
+}
+
+// handleAdminRoles is an HTTP handler function that changes the role of a client of the company.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleAdminRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "only PUT allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var change models.RoleChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		returnResponse(w, r, invalidRequest())
+		return
+	}
+
+	returnResponse(w, r, SetRole(change))
+}
+
+// handleAdminAudit is an HTTP handler function that lists the latest entries of the audit log. The optional
+// query parameters limit the number of entries (limit) and keep only the entries of a client (username).
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleAdminAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := AUDIT_LIMIT
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			returnResponse(w, r, invalidRequest())
+			return
+		}
+		limit = n
+	}
+
+	returnResponse(w, r, GetAudit(limit, r.URL.Query().Get("username")))
+}
+
+// SetRole changes the role of a client of the company. Federated clients are always customers. The role
+// signed in the access tokens already given to the client changes when they are refreshed.
+//
+// Parameters:
+//   - change: The username of the client and its new role.
+//
+// Return:
+//   - A response with the client and its role, a 400 Bad Request when the role is unknown
+//     or a 404 Not Found when the client is not a client of this company.
+func SetRole(change models.RoleChange) models.Response {
+	if !models.ValidRole(change.Role) {
+		return models.Response{
+			Error:  "not valid role " + change.Role,
+			Code:   models.CodeInvalidRole,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	client, err := dao.GetClientDAO().FindByUsername(change.Username)
+	if err != nil || client.HomeCompany != "" {
+		return models.Response{
+			Error:  "client not found",
+			Code:   models.CodeClientNotFound,
+			Status: http.StatusNotFound,
+		}
+	}
+
+	client.Role = change.Role
+	if err := dao.GetClientDAO().Update(*client); err != nil {
+		return internalError("failed to update client")
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"user": map[string]interface{}{
+				"Username": client.Username,
+				"Role":     client.Role,
+			},
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// GetAudit lists the latest entries of the audit log, with the usernames of the acting and affected clients.
+//
+// Parameters:
+//   - limit: The maximum number of entries.
+//   - username: The username of a client to keep only its entries, or empty for all of them.
+func GetAudit(limit int, username string) models.Response {
+	var clientId uint
+	if username != "" {
+		client, err := dao.GetClientDAO().FindByUsername(username)
+		if err != nil {
+			return models.Response{
+				Error:  "client not found",
+				Code:   models.CodeClientNotFound,
+				Status: http.StatusNotFound,
+			}
+		}
+		clientId = client.ID
+	}
+
+	entries, err := dao.GetAuditDAO().FindRecent(limit, clientId)
+	if err != nil {
+		return internalError("failed to find audit entries")
+	}
+
+	// Nomes de usuário já buscados, para não repetir a busca em cada entrada
+	usernames := map[uint]string{0: ""}
+	name := func(id uint) string {
+		if _, found := usernames[id]; !found {
+			if client, err := dao.GetClientDAO().FindById(id); err == nil {
+				usernames[id] = client.Username
+			}
+		}
+		return usernames[id]
+	}
+
+	audit := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		audit = append(audit, map[string]interface{}{
+			"Time":   entry.CreatedAt,
+			"Actor":  name(entry.ActorId),
+			"Role":   entry.Role,
+			"Client": name(entry.ClientId),
+			"Method": entry.Method,
+			"Path":   entry.Path,
+			"Status": entry.Status,
+		})
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"audit": audit,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// auditCommand records in the audit log a command of the CLI that affected a client.
+func auditCommand(command string, username string, status int) {
+	entry := models.AuditEntry{Role: models.ActorCLI, Method: "CLI", Path: command, Status: status}
+	if client, err := dao.GetClientDAO().FindByUsername(username); err == nil {
+		entry.ClientId = client.ID
+	}
+	dao.GetAuditDAO().Insert(entry)
+}
+
+func forbidden(msg string) models.Response {
+	return models.Response{
+		Error:  msg,
+		Code:   models.CodeForbidden,
+		Status: http.StatusForbidden,
+	}
+}
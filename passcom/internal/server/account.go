@@ -0,0 +1,439 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"strings"
+)
+
+// handleRegister handles HTTP POST requests to sign up a new client.
+// It decodes the registration from the request body and returns the created client with a 201 Created status.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleRegister(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var registration models.Registration
+	if err := json.NewDecoder(r.Body).Decode(&registration); err != nil {
+		returnResponse(w, r, invalidRequest())
+		return
+	}
+
+	returnResponse(w, r, Register(registration))
+}
+
+// handleUser handles the profile of the authenticated client: GET retrieves it, PUT updates it
+// and DELETE removes the account.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleUser(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	switch r.Method {
+	case http.MethodGet:
+		handleGetUser(w, r)
+	case http.MethodPut:
+		handleUpdateProfile(w, r)
+	case http.MethodDelete:
+		handleDeleteAccount(w, r)
+	default:
+		http.Error(w, "only GET, PUT, DELETE allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+func handleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+
+	var change models.ProfileChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		returnResponse(w, r, invalidRequest())
+		return
+	}
+
+	response := UpdateProfile(models.Request{
+		Auth: token,
+		Data: change,
+	})
+	returnResponse(w, r, response)
+}
+
+func handleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+
+	var deletion models.AccountDeletion
+	if err := json.NewDecoder(r.Body).Decode(&deletion); err != nil {
+		returnResponse(w, r, invalidRequest())
+		return
+	}
+
+	response := DeleteAccount(models.Request{
+		Auth: token,
+		Data: deletion,
+	})
+	returnResponse(w, r, response)
+}
+
+// handleChangePassword handles HTTP PUT requests to change the password of the authenticated client.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPut {
+		http.Error(w, "only PUT allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+
+	var change models.PasswordChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		returnResponse(w, r, invalidRequest())
+		return
+	}
+
+	response := ChangePassword(models.Request{
+		Auth: token,
+		Data: change,
+	})
+	returnResponse(w, r, response)
+}
+
+// Register creates a new client with a validated name, username and password. The password is stored hashed.
+//
+// Parameters:
+//   - registration: The name, username and password of the new client.
+//
+// Return:
+//   - A response with the created client, or a 400 Bad Request when a field is not valid
+//     and a 409 Conflict when the username is already taken.
+func Register(registration models.Registration) models.Response {
+	registration.Name = strings.TrimSpace(registration.Name)
+
+	if err := registration.Validate(); err != nil {
+		return validationError(err)
+	}
+
+	if _, err := dao.GetClientDAO().FindByUsername(registration.Username); err == nil {
+		return usernameTaken()
+	}
+
+	hash, err := utils.HashPassword(registration.Password)
+	if err != nil {
+		return internalError("failed to hash password")
+	}
+
+	client := models.Client{
+		Name:     registration.Name,
+		Username: registration.Username,
+		Password: hash,
+	}
+
+	if err := dao.GetClientDAO().Insert(client); err != nil {
+		// Outro cadastro pode ter usado o nome de usuário entre a busca e a inserção
+		if _, err := dao.GetClientDAO().FindByUsername(registration.Username); err == nil {
+			return usernameTaken()
+		}
+		return internalError("failed to create client")
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"user": map[string]interface{}{
+				"Name":     client.Name,
+				"Username": client.Username,
+			},
+		},
+		Status: http.StatusCreated,
+	}
+}
+
+// UpdateProfile changes the name and/or the username of the authenticated client.
+//
+// Parameters:
+//   - request: The request with the session token and a models.ProfileChange.
+//
+// Return:
+//   - A response with the updated profile, or a 400 Bad Request when a field is not valid
+//     and a 409 Conflict when the new username is already taken.
+func UpdateProfile(request models.Request) models.Response {
+	client, failure := authenticatedClient(request.Auth)
+	if failure != nil {
+		return *failure
+	}
+
+	var change models.ProfileChange
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &change)
+
+	if change.Name != nil {
+		name := strings.TrimSpace(*change.Name)
+		if err := models.ValidateName(name); err != nil {
+			return validationError(err)
+		}
+		client.Name = name
+	}
+
+	if change.Username != nil && *change.Username != client.Username {
+		if err := models.ValidateUsername(*change.Username); err != nil {
+			return validationError(err)
+		}
+		if other, err := dao.GetClientDAO().FindByUsername(*change.Username); err == nil && other.ID != client.ID {
+			return usernameTaken()
+		}
+		client.Username = *change.Username
+	}
+
+	// Os tickets carregados com o cliente não são salvos de novo
+	client.ClientFlights = nil
+	if err := dao.GetClientDAO().Update(*client); err != nil {
+		if other, err := dao.GetClientDAO().FindByUsername(client.Username); err == nil && other.ID != client.ID {
+			return usernameTaken()
+		}
+		return internalError("failed to update client")
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"user": map[string]interface{}{
+				"Name":     client.Name,
+				"Username": client.Username,
+			},
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// ChangePassword replaces the password of the authenticated client after checking the current one.
+// The other devices of the client are logged out, so only the device that changed the password stays logged in.
+//
+// Parameters:
+//   - request: The request with the session token and a models.PasswordChange.
+//
+// Return:
+//   - A response with a success message, a 403 Forbidden when the current password is wrong
+//     or a 400 Bad Request when the new password is not valid.
+func ChangePassword(request models.Request) models.Response {
+	client, failure := authenticatedClient(request.Auth)
+	if failure != nil {
+		return *failure
+	}
+
+	var change models.PasswordChange
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &change)
+
+	if match, _ := utils.VerifyPassword(client.Password, change.CurrentPassword); !match {
+		return wrongPassword()
+	}
+
+	if err := models.ValidatePassword(change.NewPassword); err != nil {
+		return validationError(err)
+	}
+
+	hash, err := utils.HashPassword(change.NewPassword)
+	if err != nil {
+		return internalError("failed to hash password")
+	}
+
+	client.Password = hash
+	client.ClientFlights = nil
+	if err := dao.GetClientDAO().Update(*client); err != nil {
+		return internalError("failed to update client")
+	}
+
+	session, _ := SessionIfExists(request.Auth)
+	revokeSessions(client.ID, session.ID.String())
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg": "password changed",
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// DeleteAccount removes the account of the authenticated client after checking its password.
+// Before the client is removed, its active holds are released, its offered rebookings are refunded and
+// its tickets are cancelled, giving the seats back to the flights. When the company that owns the flight
+// of a ticket doesn't cancel it, the account is kept so the client can try again.
+//
+// Parameters:
+//   - request: The request with the session token and a models.AccountDeletion.
+//
+// Return:
+//   - A response with the amount refunded, a 403 Forbidden when the password is wrong or a 409 Conflict
+//     with the tickets that could not be cancelled.
+func DeleteAccount(request models.Request) models.Response {
+	client, failure := authenticatedClient(request.Auth)
+	if failure != nil {
+		return *failure
+	}
+
+	var deletion models.AccountDeletion
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &deletion)
+
+	if match, _ := utils.VerifyPassword(client.Password, deletion.Password); !match {
+		return wrongPassword()
+	}
+
+	if holds, err := dao.GetHoldDAO().FindByClient(client.ID); err == nil {
+		for _, hold := range holds {
+			if hold.Status == models.PENDING {
+				instance.releaseHold(hold)
+			}
+		}
+	}
+
+	var refund uint
+
+	if rebookings, err := dao.GetRebookingDAO().FindByClient(client.ID); err == nil {
+		for _, rebooking := range rebookings {
+			if rebooking.Status != models.RebookingOffered {
+				continue
+			}
+			rebooking.Status = models.RebookingRefunded
+			rebooking.Refund = rebooking.Price
+			dao.GetRebookingDAO().Update(rebooking)
+			refund += rebooking.Refund
+		}
+	}
+
+	pending := make([]uint, 0)
+	for _, ticket := range client.ClientFlights {
+		amount, cancelled := cancelTicket(ticket)
+		if !cancelled {
+			pending = append(pending, ticket.ID)
+			continue
+		}
+		refund += amount
+	}
+
+	if len(pending) > 0 {
+		return models.Response{
+			Error: "tickets not cancelled",
+			Code:  models.CodeTicketsPending,
+			Data: map[string]interface{}{
+				"tickets": pending,
+				"Refund":  refund,
+			},
+			Status: http.StatusConflict,
+		}
+	}
+
+	revokeSessions(client.ID, "")
+
+	client.ClientFlights = nil
+	if err := dao.GetClientDAO().Delete(*client); err != nil {
+		return internalError("failed to delete client")
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg":    "account deleted",
+			"Refund": refund,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// authenticatedClient finds the client of a session token, with its tickets.
+//
+// Return:
+//   - The client of the session.
+//   - nil, or the response to be returned when there is no session, the client no longer exists
+//     or it is a federated client, whose account is kept by its home company.
+func authenticatedClient(auth string) (*models.Client, *models.Response) {
+	session, exists := SessionIfExists(auth)
+	if !exists {
+		failure := notAuthorized()
+		return nil, &failure
+	}
+
+	client, err := dao.GetClientDAO().FindById(session.ClientID)
+	if err != nil {
+		return nil, &models.Response{
+			Error:  "client not found",
+			Code:   models.CodeClientNotFound,
+			Status: http.StatusNotFound,
+		}
+	}
+
+	// A conta do cliente federado é mantida pela companhia de origem
+	if client.HomeCompany != "" {
+		return nil, &models.Response{
+			Error:  "account managed by " + client.HomeCompany,
+			Code:   models.CodeFederated,
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return client, nil
+}
+
+// validationError returns the 400 Bad Request of a field that failed the validation, with the code of the field.
+func validationError(err error) models.Response {
+	code := models.CodeInvalidRequest
+	switch err {
+	case models.ErrInvalidName:
+		code = models.CodeInvalidName
+	case models.ErrInvalidUsername:
+		code = models.CodeInvalidUsername
+	case models.ErrInvalidPassword:
+		code = models.CodeInvalidPassword
+	}
+
+	return models.Response{
+		Error:  err.Error(),
+		Code:   code,
+		Status: http.StatusBadRequest,
+	}
+}
+
+func invalidRequest() models.Response {
+	return models.Response{
+		Error:  "invalid request body",
+		Code:   models.CodeInvalidRequest,
+		Status: http.StatusBadRequest,
+	}
+}
+
+func usernameTaken() models.Response {
+	return models.Response{
+		Error:  "username already taken",
+		Code:   models.CodeUsernameTaken,
+		Status: http.StatusConflict,
+	}
+}
+
+func wrongPassword() models.Response {
+	return models.Response{
+		Error:  "wrong password",
+		Code:   models.CodeWrongPassword,
+		Status: http.StatusForbidden,
+	}
+}
+
+func internalError(msg string) models.Response {
+	return models.Response{
+		Error:  msg,
+		Code:   models.CodeInternal,
+		Status: http.StatusInternalServerError,
+	}
+}
@@ -0,0 +1,451 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// isAdminToken checks the admin token sent in the Authorization header against the configured one.
+// The admin token is not accepted when none is configured.
+func isAdminToken(r *http.Request) bool {
+	token := r.Header.Get("Authorization")
+	return cfg.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(cfg.AdminToken)) == 1
+}
+
+// handleAdminFlights is an HTTP handler function that lets the company manage its own flights at runtime.
+// GET lists the flights of the company, POST creates a flight, PUT edits the flight given by the id query
+// parameter (its UniqueId) and DELETE cancels it. Every change is broadcast to the connected servers.
+// Access is checked by the authorize middleware.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func (s *System) handleAdminFlights(w http.ResponseWriter, r *http.Request) {
+	var change models.FlightChange
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			returnResponse(w, r, models.Response{
+				Error:  "invalid flight data",
+				Status: http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	id := r.URL.Query().Get("id")
+
+	switch r.Method {
+	case http.MethodGet:
+		flights, _ := dao.GetFlightDAO().FindByCompany(s.ServerName)
+		returnResponse(w, r, models.Response{
+			Data:   map[string]interface{}{"Flights": flights},
+			Status: http.StatusOK,
+		})
+	case http.MethodPost:
+		returnResponse(w, r, s.CreateFlight(change))
+	case http.MethodPut:
+		returnResponse(w, r, s.EditFlight(id, change))
+	case http.MethodDelete:
+		returnResponse(w, r, s.CancelFlight(id))
+	default:
+		http.Error(w, "only GET, POST, PUT and DELETE allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAdminFlightStatus is an HTTP handler function that changes the status of a flight of the company,
+// given by the id query parameter (its UniqueId). The new status is broadcast to the connected servers.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func (s *System) handleAdminFlightStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "only PUT allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var change models.FlightStatusChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		returnResponse(w, r, models.Response{
+			Error:  "invalid status data",
+			Status: http.StatusBadRequest,
+		})
+		return
+	}
+
+	returnResponse(w, r, s.SetFlightStatus(r.URL.Query().Get("id"), change))
+}
+
+// CreateFlight creates a flight of this company and broadcasts it to the connected servers.
+//
+// Parameters:
+//   - change: The airports, price and capacity or fare classes of the flight, and optionally its departure and arrival.
+//
+// Return:
+//   - A response with the created flight, or an error response if the data is not valid.
+func (s *System) CreateFlight(change models.FlightChange) models.Response {
+	if change.Fares != nil {
+		if change.Price != nil || change.Capacity != nil {
+			return models.Response{
+				Error:  "price and capacity are defined by the fares",
+				Status: http.StatusBadRequest,
+			}
+		}
+	} else if change.Capacity == nil && change.Layout != nil {
+		capacity := change.Layout.Count()
+		change.Capacity = &capacity
+	}
+
+	if change.Fares == nil && (change.Price == nil || change.Capacity == nil) {
+		return models.Response{
+			Error:  "price and capacity are required",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	origin, errOrigin := dao.GetAirportDAO().FindById(change.OriginAirportID)
+	destination, errDestination := dao.GetAirportDAO().FindById(change.DestinationAirportID)
+	if errOrigin != nil || errDestination != nil || origin.ID == destination.ID {
+		return models.Response{
+			Error:  "not valid airports",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	uniqueId, _ := uuid.NewV7()
+	flight := models.Flight{
+		Company:              s.ServerName,
+		UniqueId:             uniqueId.String(),
+		OriginAirportID:      origin.ID,
+		DestinationAirportID: destination.ID,
+	}
+	if errResponse := applyFlightChange(&flight, change); errResponse != nil {
+		return *errResponse
+	}
+
+	s.Lock.Lock()
+	s.stampFlight(&flight)
+	dao.GetFlightDAO().Insert(flight)
+	s.Lock.Unlock()
+
+	created, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if err != nil {
+		return models.Response{
+			Error:  "flight not created",
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	s.broadcast(*created)
+
+	return models.Response{
+		Data:   map[string]interface{}{"Flight": created},
+		Status: http.StatusCreated,
+	}
+}
+
+// EditFlight changes the price, capacity, fare classes or schedule of a flight of this company and broadcasts it.
+// Flights of other companies can't be edited, and the capacity can't be reduced below the seats already taken.
+//
+// Parameters:
+//   - uniqueId: The UniqueId of the flight.
+//   - change: The fields to change.
+//
+// Return:
+//   - A response with the updated flight, or an error response.
+func (s *System) EditFlight(uniqueId string, change models.FlightChange) models.Response {
+	s.Lock.Lock()
+
+	flight, errResponse := s.ownFlight(uniqueId)
+	if errResponse != nil {
+		s.Lock.Unlock()
+		return *errResponse
+	}
+
+	if errResponse := applyFlightChange(flight, change); errResponse != nil {
+		s.Lock.Unlock()
+		return *errResponse
+	}
+
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+	s.Lock.Unlock()
+
+	s.broadcast(*flight)
+
+	return models.Response{
+		Data:   map[string]interface{}{"Flight": flight},
+		Status: http.StatusOK,
+	}
+}
+
+// CancelFlight removes a flight of this company and broadcasts the removal, so the replicas remove it too.
+// The tickets of the flight are rebooked or refunded by the server of each client (see rebookTickets), and the
+// servers that bought seats through the two-phase commit are told which of their tickets were affected.
+//
+// Parameters:
+//   - uniqueId: The UniqueId of the flight.
+//
+// Return:
+//   - A response with the cancelled flight, or an error response.
+func (s *System) CancelFlight(uniqueId string) models.Response {
+	s.Lock.Lock()
+
+	flight, errResponse := s.ownFlight(uniqueId)
+	if errResponse != nil {
+		s.Lock.Unlock()
+		return *errResponse
+	}
+
+	// A remoção é uma nova versão do voo, para que as réplicas a apliquem como qualquer outra alteração
+	s.stampFlight(flight)
+	flight.Status = models.FlightCancelled
+	flight.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	dao.GetFlightDAO().Update(*flight)
+	s.Lock.Unlock()
+
+	s.broadcast(*flight)
+	rebookTickets(*flight)
+	s.notifyCoordinators(*flight)
+
+	return models.Response{
+		Data:   map[string]interface{}{"Flight": flight},
+		Status: http.StatusOK,
+	}
+}
+
+// SetFlightStatus changes the status and the estimated departure of a flight of this company and broadcasts it.
+// Setting the status to models.FlightCancelled cancels the flight, as in CancelFlight.
+//
+// Parameters:
+//   - uniqueId: The UniqueId of the flight.
+//   - change: The new status and, optionally, the new estimated departure.
+//
+// Return:
+//   - A response with the updated flight, or an error response.
+func (s *System) SetFlightStatus(uniqueId string, change models.FlightStatusChange) models.Response {
+	if change.Status == models.FlightCancelled {
+		return s.CancelFlight(uniqueId)
+	}
+
+	s.Lock.Lock()
+
+	flight, errResponse := s.ownFlight(uniqueId)
+	if errResponse != nil {
+		s.Lock.Unlock()
+		return *errResponse
+	}
+
+	if err := flight.ChangeStatus(change.Status, change.EstimatedDeparture); err != nil {
+		s.Lock.Unlock()
+		return models.Response{
+			Error:  err.Error(),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+	s.Lock.Unlock()
+
+	s.broadcast(*flight)
+
+	return models.Response{
+		Data:   map[string]interface{}{"Flight": flight},
+		Status: http.StatusOK,
+	}
+}
+
+// ownFlight finds a flight that this server may change. The caller must hold the system lock.
+//
+// Return:
+//   - The flight, or an error response if it doesn't exist or belongs to another company.
+func (s *System) ownFlight(uniqueId string) (*models.Flight, *models.Response) {
+	flight, err := dao.GetFlightDAO().FindByUniqueId(uniqueId)
+	if err != nil {
+		return nil, &models.Response{
+			Error:  "flight not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if flight.Company != s.ServerName {
+		return nil, &models.Response{
+			Error:  "flight belongs to " + flight.Company,
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return flight, nil
+}
+
+// applyFlightChange validates a change and applies it to a flight. When the capacity changes,
+// the seats already taken are kept and the available seats follow the new capacity. The layout can only
+// be replaced while no seat is assigned, and the capacity can't exceed its seats or leave out an assigned seat.
+// A flight with fare classes has its price and capacity defined by them (see applyFares).
+//
+// Return:
+//   - An error response if the change is not valid, or nil.
+func applyFlightChange(flight *models.Flight, change models.FlightChange) *models.Response {
+	if (change.Price != nil || change.Capacity != nil) && (len(flight.Fares) > 0 || change.Fares != nil) {
+		return &models.Response{
+			Error:  "price and capacity are defined by the fares",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if change.Price != nil {
+		flight.Price = *change.Price
+	}
+
+	if change.Layout != nil {
+		if err := change.Layout.Validate(); err != nil {
+			return &models.Response{
+				Error:  err.Error(),
+				Status: http.StatusBadRequest,
+			}
+		}
+		if flight.OccupiedSeats() > 0 {
+			return &models.Response{
+				Error:  "layout can't change after seats are assigned",
+				Status: http.StatusConflict,
+			}
+		}
+		flight.Layout = *change.Layout
+	}
+
+	if change.Capacity != nil {
+		sold := flight.Sold()
+		if *change.Capacity <= 0 {
+			return &models.Response{
+				Error:  "capacity must be positive",
+				Status: http.StatusBadRequest,
+			}
+		}
+		if *change.Capacity < sold {
+			return &models.Response{
+				Error:  "capacity below the seats already sold",
+				Status: http.StatusConflict,
+			}
+		}
+		flight.Capacity = *change.Capacity
+		flight.Seats = *change.Capacity - sold
+	}
+
+	if change.Fares != nil {
+		if errResponse := applyFares(flight, change.Fares); errResponse != nil {
+			return errResponse
+		}
+	}
+
+	if len(flight.Layout) > 0 && flight.Capacity > flight.Layout.Count() {
+		return &models.Response{
+			Error:  "capacity exceeds the seats of the layout",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	// Um assento atribuído não pode ficar fora do mapa de assentos
+	taken := 0
+	for _, seat := range flight.SeatMap() {
+		if seat.Taken {
+			taken++
+		}
+	}
+	if taken < flight.OccupiedSeats() {
+		return &models.Response{
+			Error:  "capacity leaves out assigned seats",
+			Status: http.StatusConflict,
+		}
+	}
+
+	// A classe com uma cabine no layout não pode ter mais assentos que ela
+	if len(flight.Layout) > 0 {
+		cabins := make(map[string]int)
+		for _, seat := range flight.SeatMap() {
+			cabins[seat.Cabin]++
+		}
+		for _, fare := range flight.Fares {
+			if seats, exists := cabins[fare.Class]; exists && fare.Capacity > seats {
+				return &models.Response{
+					Error:  "fare class " + fare.Class + " exceeds the seats of its cabin",
+					Status: http.StatusBadRequest,
+				}
+			}
+		}
+	}
+
+	if change.Departure != nil {
+		flight.Departure = *change.Departure
+	}
+	if change.Arrival != nil {
+		flight.Arrival = *change.Arrival
+	}
+	if flight.Departure.IsZero() != flight.Arrival.IsZero() || (flight.Scheduled() && !flight.Arrival.After(flight.Departure)) {
+		return &models.Response{
+			Error:  "not valid departure and arrival",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return nil
+}
+
+// applyFares replaces the fare classes of a flight. The seats already taken in each class are kept, so a class
+// can't be removed or get a capacity below them. The capacity and available seats of the flight become the sum
+// of its classes, and its price the price of the first class.
+//
+// Return:
+//   - An error response if the fares are not valid, or nil.
+func applyFares(flight *models.Flight, fares []models.Fare) *models.Response {
+	if err := models.ValidateFares(fares); err != nil {
+		return &models.Response{
+			Error:  err.Error(),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	sold := make(map[string]int)
+	for _, fare := range flight.FareClasses() {
+		if taken := fare.Capacity - fare.Seats; taken > 0 {
+			sold[fare.Class] = taken
+		}
+	}
+
+	replaced := make([]models.Fare, len(fares))
+	capacity, seats := 0, 0
+	for i, fare := range fares {
+		if fare.Capacity < sold[fare.Class] {
+			return &models.Response{
+				Error:  "capacity of fare class " + fare.Class + " below the seats already sold",
+				Status: http.StatusConflict,
+			}
+		}
+		fare.Seats = fare.Capacity - sold[fare.Class]
+		delete(sold, fare.Class)
+
+		replaced[i] = fare
+		capacity += fare.Capacity
+		seats += fare.Seats
+	}
+
+	if len(sold) > 0 {
+		return &models.Response{
+			Error:  "fare class with seats already sold can't be removed",
+			Status: http.StatusConflict,
+		}
+	}
+
+	flight.Fares = replaced
+	flight.Capacity = capacity
+	flight.Seats = seats
+	flight.Price = replaced[0].Price
+	return nil
+}
@@ -2,7 +2,7 @@ package server
 
 import (
 	"net/http"
-	"rumos/internal/models"
+	"passcom/internal/models"
 )
 
 func handleGetAirports(w http.ResponseWriter, r *http.Request) {
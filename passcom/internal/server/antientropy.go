@@ -0,0 +1,304 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+type repairRequest struct {
+	Company string
+	Buckets []int
+	Flights []models.Flight
+}
+
+type repairResponse struct {
+	Flights  []models.Flight
+	Repaired int
+}
+
+// antiEntropyReport summarizes an anti-entropy round with a connection.
+type antiEntropyReport struct {
+	Peer      string
+	Companies []string // Companhias cujos conjuntos de voos divergiam
+	Pulled    int      // Voos reparados localmente
+	Pushed    int      // Voos reparados no servidor remoto
+}
+
+func (r antiEntropyReport) String() string {
+	if len(r.Companies) == 0 {
+		return fmt.Sprintf("%s: replicas already consistent\n", r.Peer)
+	}
+	return fmt.Sprintf("%s: repaired %d local and %d remote flights (companies: %s)\n",
+		r.Peer, r.Pulled, r.Pushed, strings.Join(r.Companies, ", "))
+}
+
+// flightBucket returns the leaf of the Merkle tree that holds the given flight.
+func flightBucket(uniqueId string) int {
+	hash := sha256.Sum256([]byte(uniqueId))
+	return int(hash[0]) % ANTI_ENTROPY_BUCKETS
+}
+
+// flightDigests builds, for each company, a Merkle tree over its flights, including the cancelled ones.
+// Each leaf hashes the UniqueId, seats, version and cancellation of the flights of one bucket, so any
+// divergent replica, or a replica that missed a cancellation, changes the root.
+func flightDigests(flights []models.Flight) map[string][][]string {
+	sort.Slice(flights, func(i, j int) bool { return flights[i].UniqueId < flights[j].UniqueId })
+
+	buckets := make(map[string][]string)
+	for _, flight := range flights {
+		if buckets[flight.Company] == nil {
+			buckets[flight.Company] = make([]string, ANTI_ENTROPY_BUCKETS)
+		}
+		bucket := flightBucket(flight.UniqueId)
+		buckets[flight.Company][bucket] += fmt.Sprintf("%s:%d:%d:%t;", flight.UniqueId, flight.Seats, flight.Version, flight.DeletedAt.Valid)
+	}
+
+	digests := make(map[string][][]string, len(buckets))
+	for company, contents := range buckets {
+		leaves := make([]string, ANTI_ENTROPY_BUCKETS)
+		for i, content := range contents {
+			leaves[i] = utils.HashString(content)
+		}
+		digests[company] = utils.MerkleTree(leaves)
+	}
+
+	return digests
+}
+
+// flightsInBuckets returns the flights of a company that belong to the given buckets.
+func flightsInBuckets(flights []models.Flight, company string, buckets []int) []models.Flight {
+	selected := make(map[int]bool, len(buckets))
+	for _, bucket := range buckets {
+		selected[bucket] = true
+	}
+
+	found := utils.Filter(flights, func(f models.Flight) bool {
+		return f.Company == company && selected[flightBucket(f.UniqueId)]
+	})
+	if found == nil {
+		found = make([]models.Flight, 0)
+	}
+	return found
+}
+
+// repairFlights merges flights received from another replica into the local one.
+// Flights of this server are never replaced, since this server is their source of truth; likewise,
+// the flights sent by their own company replace the local copy whenever they differ. A cancelled flight
+// received is applied as a tombstone, rebooking the tickets of the local clients, and a flight already
+// cancelled locally is never restored.
+// The caller must hold the system lock.
+//
+// Return:
+//   - The number of local flights that were inserted or changed.
+func (s *System) repairFlights(flights []models.Flight, sender string) int {
+	repaired := 0
+	for _, flight := range flights {
+		if flight.Company == s.ServerName {
+			continue
+		}
+
+		if flight.Company == sender {
+			if local, err := dao.GetFlightDAO().FindByUniqueIdWithDeleted(flight.UniqueId); err == nil {
+				if local.DeletedAt.Valid || (local.Seats == flight.Seats && local.Version == flight.Version &&
+					!flight.DeletedAt.Valid) {
+					continue
+				}
+				cancelled := copyFlightState(local, flight)
+				dao.GetFlightDAO().Update(*local)
+				if cancelled {
+					rebookTickets(*local)
+				}
+				repaired++
+				continue
+			}
+		}
+
+		if s.mergeFlight(flight) {
+			repaired++
+		}
+	}
+	return repaired
+}
+
+// HandleDigest returns the Merkle trees of the flights stored by this server, cancelled ones included,
+// grouped by company.
+func (s *System) HandleDigest(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), msg.From, s.VectorClock, flightDigests(dao.GetFlightDAO().FindAllWithDeleted()))
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+}
+
+// HandleRepair receives the flights of the divergent buckets of a company from another server,
+// merges them and replies with the local flights of the same buckets.
+func (s *System) HandleRepair(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	var request repairRequest
+	if err := decodeBody(msg.Body, &request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	repaired := s.repairFlights(request.Flights, s.Connections[msg.From].Name)
+	s.Lock.Unlock()
+
+	response := repairResponse{
+		Flights:  flightsInBuckets(dao.GetFlightDAO().FindAllWithDeleted(), request.Company, request.Buckets),
+		Repaired: repaired,
+	}
+
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), msg.From, s.VectorClock, response)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+}
+
+// antiEntropy runs an anti-entropy round with a connection. The Merkle trees of both replicas are compared
+// company by company, and only the flights of the divergent buckets are exchanged and merged on both sides.
+func (s *System) antiEntropy(id string, conn models.Connection) (antiEntropyReport, error) {
+	report := antiEntropyReport{Peer: conn.Name, Companies: make([]string, 0)}
+
+	status, response, err := s.sendServerMessage(id, conn, http.MethodGet, "/server/antientropy/digest", "")
+	if err != nil {
+		return report, err
+	}
+	if status != http.StatusOK || response == nil {
+		return report, fmt.Errorf("digest request failed with status %d", status)
+	}
+
+	var remote map[string][][]string
+	if err := decodeBody(response.Body, &remote); err != nil {
+		return report, err
+	}
+
+	flights := dao.GetFlightDAO().FindAllWithDeleted()
+	local := flightDigests(flights)
+
+	companies := make([]string, 0, len(local)+len(remote))
+	for company := range local {
+		companies = append(companies, company)
+	}
+	for company := range remote {
+		if _, exists := local[company]; !exists {
+			companies = append(companies, company)
+		}
+	}
+	sort.Strings(companies)
+
+	for _, company := range companies {
+		buckets := utils.MerkleDiff(local[company], remote[company])
+		if len(buckets) == 0 {
+			continue
+		}
+
+		request := repairRequest{
+			Company: company,
+			Buckets: buckets,
+			Flights: flightsInBuckets(flights, company, buckets),
+		}
+
+		status, response, err := s.sendServerMessage(id, conn, http.MethodPost, "/server/antientropy/repair", request)
+		if err != nil {
+			return report, err
+		}
+		if status != http.StatusOK || response == nil {
+			return report, fmt.Errorf("repair of %s failed with status %d", company, status)
+		}
+
+		var repair repairResponse
+		if err := decodeBody(response.Body, &repair); err != nil {
+			return report, err
+		}
+
+		s.Lock.Lock()
+		report.Pulled += s.repairFlights(repair.Flights, conn.Name)
+		s.Lock.Unlock()
+
+		report.Pushed += repair.Repaired
+		report.Companies = append(report.Companies, company)
+	}
+
+	return report, nil
+}
+
+// runAntiEntropy runs an anti-entropy round with every online connection.
+func (s *System) runAntiEntropy() []antiEntropyReport {
+	s.Lock.RLock()
+	connections := make(map[string]models.Connection, len(s.Connections))
+	for id, conn := range s.Connections {
+		connections[id] = conn
+	}
+	s.Lock.RUnlock()
+
+	reports := make([]antiEntropyReport, 0, len(connections))
+	for id, conn := range connections {
+		if !conn.IsOnline {
+			continue
+		}
+
+		report, err := s.antiEntropy(id, conn)
+		if err != nil {
+			log.Printf("Anti-entropy round with %s failed: %v", conn.Name, err)
+			continue
+		}
+
+		if len(report.Companies) > 0 {
+			log.Print("Anti-entropy round with ", report)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// periodicAntiEntropy periodically reconciles the replicas with every online connection,
+// repairing the flights whose broadcasts were lost.
+func (s *System) periodicAntiEntropy() {
+	ticker := time.NewTicker(ANTI_ENTROPY_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.runAntiEntropy()
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+func (s *System) HandleBroadcast(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	err := json.NewDecoder(r.Body).Decode(&msg)
+
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	to := msg.To
+
+	jsonData, err := json.Marshal(msg.Body)
+	if err != nil {
+		http.Error(w, "Error marshalling data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var flight models.Flight
+	err = json.Unmarshal(jsonData, &flight)
+	if err != nil {
+		http.Error(w, "Error unmarshalling data: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Somente o dono do voo o altera, então as versões enviadas por outros servidores não são aceitas
+	s.Lock.RLock()
+	sender, exists := s.Connections[msg.From]
+	s.Lock.RUnlock()
+	if !exists || sender.Name != flight.Company {
+		log.Printf("Rejecting broadcast of flight %s of %s sent by %s", flight.UniqueId, flight.Company, sender.Name)
+		http.Error(w, "Only the owner of the flight may broadcast it", http.StatusForbidden)
+		return
+	}
+
+	prevFlight, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if err != nil {
+		// Voo criado pelo dono após a sincronização; um voo já removido não é recriado
+		if !flight.DeletedAt.Valid && !dao.GetFlightDAO().ExistsByUniqueId(flight.UniqueId) {
+			flight.ID = 0
+			flight.Tickets = nil
+			dao.GetFlightDAO().Insert(flight)
+		}
+	} else if flight.Version > prevFlight.Version {
+		// Um broadcast atrasado não pode sobrescrever uma versão mais nova do voo
+		cancelled := copyFlightState(prevFlight, flight)
+		dao.GetFlightDAO().Update(*prevFlight)
+		if cancelled {
+			// Os clientes deste servidor com tickets no voo cancelado recebem uma oferta de remarcação
+			rebookTickets(*prevFlight)
+		}
+	} else {
+		log.Printf("Ignoring stale broadcast of flight %s: version %d, stored version %d",
+			flight.UniqueId, flight.Version, prevFlight.Version)
+	}
+
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), to, s.VectorClock, "")
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+}
+
+// broadcast sends the new state of a flight of this server to every connected server.
+// The message is first stored in the outbox of each connection, so it can be retried if the delivery fails
+// or if the connection is offline. Deliveries to online connections are attempted right away.
+func (s *System) broadcast(flight models.Flight) {
+	// As réplicas só aceitam os voos enviados pelo dono
+	if flight.Company != s.ServerName {
+		log.Printf("Not broadcasting flight %s of %s", flight.UniqueId, flight.Company)
+		return
+	}
+
+	s.IncrementClock()
+
+	// O relógio é copiado aqui, pois o chamador pode manter o lock do sistema enquanto as entregas terminam
+	clock := copyClock(s.VectorClock)
+
+	for id, conn := range s.Connections {
+		entry, err := s.enqueueBroadcast(id, flight)
+		if err != nil {
+			log.Printf("Error storing broadcast of flight %s to %s: %v", flight.UniqueId, conn.Name, err)
+			continue
+		}
+
+		// A mensagem será entregue quando a conexão voltar a ficar online
+		if entry == nil || !conn.IsOnline {
+			continue
+		}
+
+		// Adiciona uma nova goroutine ao WaitGroup para envio assíncrono
+		s.wg.Add(1)
+		go func(id string, conn models.Connection, entry models.OutboxMessage) {
+			defer s.wg.Done()
+			s.deliverOutbox(id, conn, entry, clock)
+		}(id, conn, *entry)
+	}
+
+	// Aguarda o término de todas as goroutines de envio
+	s.wg.Wait()
+}
+
+// sendFlight posts a broadcast message to the given URL.
+//
+// Return:
+//   - The HTTP status code of the response, or 0 if the message couldn't be sent.
+func (s *System) sendFlight(url string, flight models.Flight, message models.Message) int {
+	// Serializa a mensagem para JSON
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshalling message for flight %s: %v", flight.UniqueId, err)
+		return 0
+	}
+
+	// Envia a requisição HTTP POST ao servidor de destino
+	client := &http.Client{Timeout: CONNECTION_TIMEOUT}
+	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Printf("Error sending flight %s to %s: %v", flight.UniqueId, url, err)
+		return 0
+	}
+	defer resp.Body.Close()
+
+	// Verifica o status da resposta
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Failed to broadcast flight %s to %s, status: %s", flight.UniqueId, url, resp.Status)
+		return resp.StatusCode
+	}
+
+	log.Printf("Successfully broadcasted flight %s to %s", flight.UniqueId, url)
+	return resp.StatusCode
+}
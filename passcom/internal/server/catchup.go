@@ -0,0 +1,235 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+)
+
+type changesRequest struct {
+	Since int
+}
+
+// HandleChanges returns the flights of this server changed after the given value of its own clock entry,
+// including the ones cancelled since then. It is used by peers that fell behind, so they can recover only
+// the changes they missed.
+func (s *System) HandleChanges(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	var request changesRequest
+	if err := decodeBody(msg.Body, &request); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.RLock()
+	defer s.Lock.RUnlock()
+
+	// O relógio é copiado antes da leitura, para que qualquer alteração posterior tenha um valor maior
+	clock := copyClock(s.VectorClock)
+
+	flights, err := dao.GetFlightDAO().FindByCompanyWithDeleted(s.ServerName)
+	if err != nil {
+		http.Error(w, "Failed to find flights", http.StatusInternalServerError)
+		return
+	}
+
+	id := s.ServerId.String()
+	changed := utils.Filter(flights, func(f models.Flight) bool {
+		return f.VectorClock[id] > request.Since
+	})
+	if changed == nil {
+		changed = make([]models.Flight, 0)
+	}
+
+	responseMsg, err := models.CreateMessage(id, msg.From, clock, changed)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+}
+
+// catchUp pulls from a peer the flight changes made after the last synchronized value of its clock entry,
+// merging them into the local replica. On success, the synchronized value of the connection is advanced
+// to the highest value of the peer's clock entry stamped on the flights received.
+func (s *System) catchUp(id string, conn models.Connection) {
+	requestMsg, err := models.CreateMessage(s.ServerId.String(), id, s.VectorClock, changesRequest{Since: conn.SyncedClock})
+	if err != nil {
+		log.Printf("Error creating changes request message: %v", err)
+		return
+	}
+
+	jsonData, err := json.Marshal(requestMsg)
+	if err != nil {
+		log.Printf("Error encoding changes request message: %v", err)
+		return
+	}
+
+	url := URL_PREFIX + conn.Address + ":" + conn.Port + "/server/changes"
+	req, err := http.NewRequest(http.MethodGet, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		log.Printf("Error creating changes request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: CONNECTION_TIMEOUT}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("Error requesting changes from %s: %v", conn.Name, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Failed to retrieve changes from %s - status: %s", conn.Name, resp.Status)
+		return
+	}
+
+	var msg models.Message
+	if err := json.NewDecoder(resp.Body).Decode(&msg); err != nil {
+		log.Printf("Error decoding changes response: %v", err)
+		return
+	}
+
+	var flights []models.Flight
+	if err := decodeBody(msg.Body, &flights); err != nil {
+		log.Printf("Error unmarshalling changed flights: %v", err)
+		return
+	}
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	for _, flight := range flights {
+		// Somente os voos da própria conexão são aceitos
+		if flight.Company == conn.Name {
+			s.mergeFlight(flight)
+		}
+	}
+
+	s.UpdateClock(msg.VectorClock)
+
+	// O valor sincronizado é o maior valor do relógio da conexão gravado nos voos recebidos, e não o do relógio,
+	// que também avança com os heartbeats
+	if stored, exists := s.Connections[id]; exists {
+		for _, flight := range flights {
+			if flight.VectorClock[id] > stored.SyncedClock {
+				stored.SyncedClock = flight.VectorClock[id]
+			}
+		}
+		s.Connections[id] = stored
+	}
+
+	log.Printf("Recovered %d flight changes from %s", len(flights), conn.Name)
+}
+
+// flightChanges returns the highest value of the clock entry of this server stamped on one of its flights,
+// sent in the heartbeat replies so that the peers pull the changes only when a flight changed.
+// It is read from the flights on the first call and kept by stampFlight. The caller must hold the system lock.
+func (s *System) flightChanges() int {
+	s.changesOnce.Do(func() {
+		flights, err := dao.GetFlightDAO().FindByCompanyWithDeleted(s.ServerName)
+		if err != nil {
+			return
+		}
+		id := s.ServerId.String()
+		for _, flight := range flights {
+			if flight.VectorClock[id] > s.changes {
+				s.changes = flight.VectorClock[id]
+			}
+		}
+	})
+	return s.changes
+}
+
+// mergeFlight merges a flight received from its owner into the local replica, comparing the clocks
+// stamped on both copies. The received copy replaces the local one only if it is newer;
+// concurrent copies are resolved by resolveConcurrentFlight. A cancelled flight is kept as a tombstone,
+// which is never replaced, so a copy sent before the cancellation doesn't restore it.
+// The caller must hold the system lock.
+//
+// Return:
+//   - true if the local replica was changed, false otherwise.
+func (s *System) mergeFlight(flight models.Flight) bool {
+	local, err := dao.GetFlightDAO().FindByUniqueIdWithDeleted(flight.UniqueId)
+	if err != nil {
+		flight.ID = 0
+		flight.Tickets = nil
+		dao.GetFlightDAO().Insert(flight)
+		return true
+	}
+	if local.DeletedAt.Valid {
+		return false
+	}
+
+	cancelled := false
+	switch s.CompareClock(local.VectorClock, flight.VectorClock) {
+	case NEWER:
+		cancelled = copyFlightState(local, flight)
+	case CONCURRENT:
+		cancelled = resolveConcurrentFlight(local, flight)
+	default:
+		return false
+	}
+
+	dao.GetFlightDAO().Update(*local)
+	if cancelled {
+		rebookTickets(*local)
+	}
+	return true
+}
+
+// resolveConcurrentFlight deterministically resolves two concurrent copies of the same flight,
+// storing the result in local. The copy with fewer available seats wins, since it never offers a seat
+// taken on the other replica, and its fare classes go with its seats; on a tie, the lower price wins. The clocks of both copies are merged,
+// and the highest version is kept, so the result is newer than either of them. A cancellation wins over
+// any concurrent change; it reports whether the flight was cancelled by the received copy.
+func resolveConcurrentFlight(local *models.Flight, received models.Flight) bool {
+	if received.Seats < local.Seats || (received.Seats == local.Seats && received.Price < local.Price) {
+		local.Seats = received.Seats
+		local.Fares = received.Fares
+		local.Price = received.Price
+	}
+
+	if received.Version > local.Version {
+		local.Version = received.Version
+		// A situação e o mapa de assentos só são alterados pelo dono, então valem os da cópia mais nova
+		local.Status = received.Status
+		local.EstimatedDeparture = received.EstimatedDeparture
+		local.Layout = received.Layout
+		local.Occupancy = received.Occupancy
+	}
+
+	merged := copyClock(local.VectorClock)
+	for id, timestamp := range received.VectorClock {
+		if timestamp > merged[id] {
+			merged[id] = timestamp
+		}
+	}
+	local.VectorClock = merged
+
+	// O cancelamento do voo prevalece sobre qualquer alteração concorrente
+	cancelled := !local.DeletedAt.Valid && received.DeletedAt.Valid
+	if cancelled {
+		local.DeletedAt = received.DeletedAt
+	}
+	return cancelled
+}
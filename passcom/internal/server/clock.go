@@ -2,6 +2,7 @@ package server
 
 import (
 	"log"
+	"passcom/internal/models"
 )
 
 func (s *System) IncrementClock() {
@@ -81,3 +82,24 @@ func (s *System) UpdateClock(receivedClock map[string]int) {
 	}
 	log.Print("Server clock has been updated")
 }
+
+// stampFlight increments the system clock and stamps the flight with a copy of it and a new version,
+// marking a new change made by this server to the flight. Only the owner of a flight stamps it, since the
+// replicas discard any version that is not newer than theirs.
+func (s *System) stampFlight(flight *models.Flight) {
+	s.IncrementClock()
+	flight.Version++
+	flight.VectorClock = copyClock(s.VectorClock)
+
+	if stamp := flight.VectorClock[s.ServerId.String()]; flight.Company == s.ServerName && stamp > s.changes {
+		s.changes = stamp
+	}
+}
+
+func copyClock(clock map[string]int) map[string]int {
+	copied := make(map[string]int, len(clock))
+	for id, timestamp := range clock {
+		copied[id] = timestamp
+	}
+	return copied
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"log"
+	"net"
 	"net/http"
-	"rumos/internal/models"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"time"
 )
 
 func (s *System) handleConnect(w http.ResponseWriter, r *http.Request) {
@@ -112,6 +115,7 @@ func (s *System) RemoveConnection(id string) {
 	defer s.Lock.Unlock()
 
 	delete(s.Connections, id)
+	dao.GetOutboxDAO().DeleteByPeer(id)
 }
 
 func (s *System) UpdateConnectionStatus(id string, isOnline bool) {
@@ -125,23 +129,27 @@ func (s *System) UpdateConnectionStatus(id string, isOnline bool) {
 	}
 }
 
-func (s *System) RequestConnection(address string, port string) {
+// RequestConnection asks the server at address:port to connect and stores it as a connection.
+//
+// Return:
+//   - The ID of the connected server, or an empty string if the connection failed.
+func (s *System) RequestConnection(address string, port string) string {
 	message, err := models.CreateMessage(s.ServerId.String(), "", s.VectorClock, map[string]interface{}{
 		"Name":    s.ServerName,
-		"Address": s.ServerName, // TODO: corrigir depois, pois assim está usando o mesmo nome do container
+		"Address": cfg.Address,
 		"Port":    s.Port,
 	})
 
 	if err != nil {
 		log.Printf("Error creating connection request message: %v", err)
-		return
+		return ""
 	}
 
 	// Serializa a mensagem em JSON
 	jsonData, err := json.Marshal(message)
 	if err != nil {
 		log.Printf("Error encoding connection request message: %v", err)
-		return
+		return ""
 	}
 
 	url := URL_PREFIX + address + ":" + port
@@ -151,7 +159,7 @@ func (s *System) RequestConnection(address string, port string) {
 	req, err := http.NewRequest("POST", url+"/server/connect", bytes.NewBuffer(jsonData))
 	if err != nil {
 		log.Printf("Error creating connection request: %v", err)
-		return
+		return ""
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -159,34 +167,34 @@ func (s *System) RequestConnection(address string, port string) {
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		log.Printf("Error connecting to %s: %v", url, err)
-		return
+		return ""
 	}
 	defer resp.Body.Close()
 
 	// Verifica o status da resposta
 	if resp.StatusCode != http.StatusCreated {
 		log.Printf("Failed to connect to %s - status: %s", url, resp.Status)
-		return
+		return ""
 	}
 
 	// Lê a resposta e extrai o ID do servidor conectado
 	var responseMessage models.Message
 	if err := json.NewDecoder(resp.Body).Decode(&responseMessage); err != nil {
 		log.Printf("Error decoding connection response: %v", err)
-		return
+		return ""
 	}
 
 	// Extrai o corpo da resposta e valida os campos
 	body, ok := responseMessage.Body.(map[string]interface{})
 	if !ok {
 		log.Printf("Invalid body format in connection response")
-		return
+		return ""
 	}
 
 	name, ok := body["Name"].(string)
 	if !ok {
 		log.Printf("Invalid name format in connection response")
-		return
+		return ""
 	}
 
 	// Atualiza o destinatário com o ID do servidor recebido na resposta
@@ -203,13 +211,77 @@ func (s *System) RequestConnection(address string, port string) {
 	s.Lock.Unlock()
 
 	log.Printf("Successfully connected to server %s at %s", name, url)
+	return responseMessage.From
+}
+
+// joinPeer connects to the server at address:port and exchanges the flights of both companies with it.
+//
+// Return:
+//   - true if the connection was established, false otherwise.
+func (s *System) joinPeer(address string, port string) bool {
+	id := s.RequestConnection(address, port)
+	if id == "" {
+		return false
+	}
+
+	s.RequestDatabase(id, address, port)
+	s.SendDatabase(id, address, port)
+	return true
+}
+
+// isConnectedTo reports whether a connection to address:port is already known.
+func (s *System) isConnectedTo(address string, port string) bool {
+	s.Lock.RLock()
+	defer s.Lock.RUnlock()
+
+	for _, conn := range s.Connections {
+		if conn.Address == address && conn.Port == port {
+			return true
+		}
+	}
+	return false
+}
+
+// connectPeers joins the peers given in the configuration, retrying every PEER_RETRY_TIMER
+// until all of them are connected. Peers already known from the system variables are skipped,
+// since the heartbeats bring them back online.
+func (s *System) connectPeers() {
+	pending := make([]string, 0, len(cfg.Peers))
+	for _, peer := range cfg.Peers {
+		address, port, err := net.SplitHostPort(peer)
+		if err != nil {
+			log.Printf("Ignoring invalid peer %s: %v", peer, err)
+			continue
+		}
+		if !s.isConnectedTo(address, port) {
+			pending = append(pending, peer)
+		}
+	}
+
+	ticker := time.NewTicker(PEER_RETRY_TIMER)
+	defer ticker.Stop()
+
+	for len(pending) > 0 {
+		remaining := pending[:0]
+		for _, peer := range pending {
+			address, port, _ := net.SplitHostPort(peer)
+			if !s.joinPeer(address, port) {
+				remaining = append(remaining, peer)
+			}
+		}
+		pending = remaining
+
+		if len(pending) > 0 {
+			<-ticker.C
+		}
+	}
 }
 
 func (s *System) RequestDisconnection(address string, port string) {
 	// Cria a mensagem de desconexão
 	message, err := models.CreateMessage(s.ServerId.String(), "", s.VectorClock, map[string]interface{}{
 		"Name":    s.ServerName,
-		"Address": s.ServerName, // Certifique-se de que este campo é o endereço correto do servidor
+		"Address": cfg.Address,
 		"Port":    s.Port,
 	})
 
@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
-	"rumos/internal/dao"
-	"rumos/internal/models"
-	"rumos/internal/utils"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
 )
 
 func (s *System) handleDatabase(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,421 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"sort"
+	"strings"
+	"time"
+)
+
+// quotaState is the state of a quota exchanged between the owner of a flight and the holder of the quota.
+// In the owner's request, Returned is the cumulative number of seats the holder should have returned and
+// Sales are the sales confirmed by the owner not applied by the holder yet; in the holder's reply, Sales
+// are the sales still pending.
+type quotaState struct {
+	FlightId string
+	Granted  int
+	Returned int
+	Sold     int
+	Sales    []quotaSale
+}
+
+// quotaSale is a seat sold from a quota, with the seat and the price confirmed by the owner of the flight.
+type quotaSale struct {
+	TicketId string
+	Class    string
+	Seat     string
+	Price    uint
+}
+
+// HandleQuotaSync receives from the owner of flights the seats granted in escrow to this server, the
+// seats it should return and the seats and prices confirmed for the sales from the quotas. The returned
+// seats are limited to the ones not sold yet, and the reply carries the resulting state of each quota with
+// the sales still pending, so the owner can account for the seats sold and returned and confirm the sales.
+func (s *System) HandleQuotaSync(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	var states []quotaState
+	if err := decodeBody(msg.Body, &states); err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	// O dono do voo atribuiu o assento e confirmou o preço das vendas pendentes
+	for _, state := range states {
+		for _, sale := range state.Sales {
+			ticket, err := dao.GetTicketDAO().FindByUniqueId(sale.TicketId)
+			if err != nil || !ticket.Pending {
+				continue
+			}
+			ticket.Seat = sale.Seat
+			ticket.Class = sale.Class
+			ticket.Price = sale.Price
+			ticket.Pending = false
+			ticket.Flight = models.Flight{}
+			dao.GetTicketDAO().Update(*ticket)
+		}
+	}
+
+	pending, err := dao.GetTicketDAO().FindPending()
+	if err != nil {
+		http.Error(w, "Failed to find pending sales", http.StatusInternalServerError)
+		return
+	}
+	sales := make(map[string][]quotaSale)
+	for _, ticket := range pending {
+		sales[ticket.Flight.UniqueId] = append(sales[ticket.Flight.UniqueId], quotaSale{
+			TicketId: ticket.UniqueId,
+			Class:    ticket.Class,
+			Price:    ticket.Price,
+		})
+	}
+
+	self := s.ServerId.String()
+	reply := make([]quotaState, 0, len(states))
+	for _, state := range states {
+		quota, err := dao.GetQuotaDAO().FindByFlightAndHolder(state.FlightId, self)
+		if err != nil {
+			quota, err = dao.GetQuotaDAO().Insert(models.Quota{FlightId: state.FlightId, Holder: self})
+			if err != nil {
+				http.Error(w, "Failed to store quota", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		// Os contadores são acumulados, então uma mesma requisição pode ser recebida mais de uma vez
+		quota.Granted = max(quota.Granted, state.Granted)
+		quota.Returned = max(quota.Returned, min(state.Returned, quota.Granted-quota.Sold))
+		dao.GetQuotaDAO().Update(*quota)
+
+		reply = append(reply, quotaState{
+			FlightId: quota.FlightId,
+			Granted:  quota.Granted,
+			Returned: quota.Returned,
+			Sold:     quota.Sold,
+			Sales:    sales[quota.FlightId],
+		})
+	}
+
+	responseMsg, err := models.CreateMessage(self, msg.From, s.VectorClock, reply)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+}
+
+// RebalanceQuotas brings the quotas held by a connection on the flights of this server back to EscrowQuota.
+// Missing seats are taken from the first fare class of the flights and granted before being sent, so a failed
+// request only delays their delivery; exceeding seats are requested back. The seats returned by the connection go back to the flights.
+// The sales from the quotas reported as pending get a seat and the current price of the fare class, and are
+// confirmed to the connection right away in a new round; the confirmations are sent again until applied.
+//
+// Return:
+//   - The number of seats granted and returned in the round.
+//   - An error if the connection couldn't be reached.
+func (s *System) RebalanceQuotas(id string, conn models.Connection) (int, int, error) {
+	granted, returned := 0, 0
+	changed := make([]models.Flight, 0)
+
+	s.Lock.Lock()
+	flights, err := dao.GetFlightDAO().FindByCompany(s.ServerName)
+	if err != nil {
+		s.Lock.Unlock()
+		return 0, 0, err
+	}
+
+	quotas, err := dao.GetQuotaDAO().FindByHolder(id)
+	if err != nil {
+		s.Lock.Unlock()
+		return 0, 0, err
+	}
+
+	ledger := make(map[string]models.Quota, len(quotas))
+	for _, quota := range quotas {
+		ledger[quota.FlightId] = quota
+	}
+
+	states := make([]quotaState, 0, len(flights))
+	for _, flight := range flights {
+		quota, exists := ledger[flight.UniqueId]
+		if !exists {
+			quota = models.Quota{FlightId: flight.UniqueId, Holder: id}
+		}
+
+		returnTo := quota.Returned
+		// A cota é formada somente por assentos da primeira classe tarifária do voo
+		fare, _ := flight.Fare("")
+		if balance := quota.Balance(); balance < s.EscrowQuota && fare.Seats > 0 {
+			grant := min(s.EscrowQuota-balance, fare.Seats)
+			flight.TakeSeats(fare.Class, grant)
+			quota.Granted += grant
+			granted += grant
+
+			s.stampFlight(&flight)
+			dao.GetFlightDAO().Update(flight)
+			changed = append(changed, flight)
+		} else if balance > s.EscrowQuota {
+			returnTo += balance - s.EscrowQuota
+		}
+
+		if quota.ID == 0 {
+			if quota.Granted == 0 {
+				continue
+			}
+			dao.GetQuotaDAO().Insert(quota)
+		} else {
+			dao.GetQuotaDAO().Update(quota)
+		}
+
+		confirmed, err := dao.GetQuotaSaleDAO().FindUnacknowledged(quota.FlightId, id)
+		if err != nil {
+			s.Lock.Unlock()
+			return granted, 0, err
+		}
+		sales := make([]quotaSale, 0, len(confirmed))
+		for _, sale := range confirmed {
+			sales = append(sales, quotaSale{TicketId: sale.TicketId, Class: sale.Class, Seat: sale.Seat, Price: sale.Price})
+		}
+
+		states = append(states, quotaState{
+			FlightId: quota.FlightId,
+			Granted:  quota.Granted,
+			Returned: returnTo,
+			Sold:     quota.Sold,
+			Sales:    sales,
+		})
+	}
+	s.Lock.Unlock()
+
+	for _, flight := range changed {
+		s.broadcast(flight)
+	}
+
+	if len(states) == 0 {
+		return 0, 0, nil
+	}
+
+	status, response, err := s.sendServerMessage(id, conn, http.MethodPost, "/server/quota/sync", states)
+	if err != nil {
+		return granted, 0, err
+	}
+	if status != http.StatusOK || response == nil {
+		return granted, 0, fmt.Errorf("quota sync failed with status %d", status)
+	}
+
+	var reply []quotaState
+	if err := decodeBody(response.Body, &reply); err != nil {
+		return granted, 0, err
+	}
+
+	sent := make(map[string][]quotaSale, len(states))
+	for _, state := range states {
+		sent[state.FlightId] = state.Sales
+	}
+
+	changed = changed[:0]
+	confirmed := 0
+	s.Lock.Lock()
+	for _, state := range reply {
+		quota, err := dao.GetQuotaDAO().FindByFlightAndHolder(state.FlightId, id)
+		if err != nil {
+			continue
+		}
+
+		pending := make(map[string]bool, len(state.Sales))
+		for _, sale := range state.Sales {
+			pending[sale.TicketId] = true
+		}
+
+		// As confirmações que não estão mais pendentes foram aplicadas pela conexão
+		for _, sale := range sent[state.FlightId] {
+			if pending[sale.TicketId] {
+				continue
+			}
+			if recorded, err := dao.GetQuotaSaleDAO().FindByTicketId(sale.TicketId); err == nil {
+				recorded.Acknowledged = true
+				dao.GetQuotaSaleDAO().Update(*recorded)
+			}
+		}
+
+		flight, err := dao.GetFlightDAO().FindByUniqueId(state.FlightId)
+		if err != nil {
+			continue
+		}
+		stamp := false
+
+		// Cada venda pendente recebe um assento da classe da cota e o preço atual dessa classe
+		for _, sale := range state.Sales {
+			if _, err := dao.GetQuotaSaleDAO().FindByTicketId(sale.TicketId); err == nil {
+				continue
+			}
+			fare, _ := flight.Fare(sale.Class)
+			seat, err := flight.AssignSeat("", fare.Class)
+			if err != nil {
+				log.Printf("Error assigning seat to sale %s of flight %s: %v", sale.TicketId, flight.UniqueId, err)
+				continue
+			}
+			dao.GetQuotaSaleDAO().Insert(models.QuotaSale{
+				FlightId: flight.UniqueId,
+				Holder:   id,
+				TicketId: sale.TicketId,
+				Class:    fare.Class,
+				Seat:     seat,
+				Price:    quote(*flight, fare.Class),
+			})
+			confirmed++
+			stamp = true
+		}
+
+		if state.Returned > quota.Returned {
+			flight.ReturnSeats("", state.Returned-quota.Returned)
+			returned += state.Returned - quota.Returned
+			quota.Returned = state.Returned
+			stamp = true
+		}
+
+		if stamp {
+			s.stampFlight(flight)
+			dao.GetFlightDAO().Update(*flight)
+			changed = append(changed, *flight)
+		}
+
+		quota.Sold = max(quota.Sold, state.Sold)
+		dao.GetQuotaDAO().Update(*quota)
+	}
+	s.Lock.Unlock()
+
+	for _, flight := range changed {
+		s.broadcast(flight)
+	}
+
+	// As vendas confirmadas na rodada são enviadas à conexão sem esperar a próxima
+	if confirmed > 0 {
+		more, back, err := s.RebalanceQuotas(id, conn)
+		if err != nil {
+			return granted + more, returned, err
+		}
+		granted += more
+		returned += back
+	}
+
+	return granted, returned, nil
+}
+
+// rebalanceAllQuotas rebalances the quotas of every online connection.
+//
+// Return:
+//   - A report of the seats granted and returned with each connection.
+func (s *System) rebalanceAllQuotas() string {
+	s.Lock.RLock()
+	connections := make(map[string]models.Connection, len(s.Connections))
+	for id, conn := range s.Connections {
+		connections[id] = conn
+	}
+	s.Lock.RUnlock()
+
+	var report strings.Builder
+	for id, conn := range connections {
+		if !conn.IsOnline {
+			continue
+		}
+
+		granted, returned, err := s.RebalanceQuotas(id, conn)
+		if err != nil {
+			log.Printf("Error rebalancing quotas of %s: %v", conn.Name, err)
+			report.WriteString(fmt.Sprintf("%s: %v\n", conn.Name, err))
+			continue
+		}
+
+		if granted > 0 || returned > 0 {
+			log.Printf("Quotas of %s rebalanced: %d seats granted, %d returned", conn.Name, granted, returned)
+		}
+		report.WriteString(fmt.Sprintf("%s: %d seats granted, %d returned\n", conn.Name, granted, returned))
+	}
+
+	return report.String()
+}
+
+// periodicRebalance periodically rebalances the quotas of the online connections, so the seats sold
+// by a peer while this server was unreachable are replaced and the unused ones are returned.
+func (s *System) periodicRebalance() {
+	ticker := time.NewTicker(ESCROW_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.rebalanceAllQuotas()
+	}
+}
+
+// sellFromQuota sells a seat of a flight whose owner is unreachable, using the quota held in escrow by this server.
+// Since the owner can't assign the seat nor quote it, the ticket is pending, with the base price of the first fare
+// class last received from it, until the owner confirms the seat and the price in a quota sync.
+//
+// Return:
+//   - true if the quota had an available seat and the ticket was created, false otherwise.
+func (s *System) sellFromQuota(flight models.Flight, clientId uint) bool {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	quota, err := dao.GetQuotaDAO().FindByFlightAndHolder(flight.UniqueId, s.ServerId.String())
+	if err != nil || quota.Balance() <= 0 {
+		return false
+	}
+
+	quota.Sold++
+	if err := dao.GetQuotaDAO().Update(*quota); err != nil {
+		return false
+	}
+
+	fare, _ := flight.Fare("")
+	dao.GetTicketDAO().Insert(models.Ticket{
+		ClientId: clientId,
+		FlightId: flight.ID,
+		Class:    fare.Class,
+		Price:    fare.Price,
+		Pending:  true,
+	})
+
+	log.Printf("Sold seat of flight %s from escrow quota, %d seats left", flight.UniqueId, quota.Balance())
+	return true
+}
+
+// quotaInfo describes the quotas granted and held by this server. The caller must hold the system lock.
+func (s *System) quotaInfo() string {
+	self := s.ServerId.String()
+	lines := make([]string, 0)
+
+	for _, quota := range dao.GetQuotaDAO().FindAll() {
+		if quota.Holder == self {
+			lines = append(lines, fmt.Sprintf("  held flight %s: %d available, %d sold",
+				quota.FlightId, quota.Balance(), quota.Sold))
+		} else if quota.Granted > 0 {
+			lines = append(lines, fmt.Sprintf("  granted flight %s to %s: %d available, %d sold",
+				quota.FlightId, s.Connections[quota.Holder].Name, quota.Balance(), quota.Sold))
+		}
+	}
+	sort.Strings(lines)
+
+	return fmt.Sprintf("Escrow Quota: %d seats per connection\nQuotas:\n%s\n", s.EscrowQuota, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"sort"
+	"strings"
+)
+
+// federatedLogin logs in a client of another company. The credentials are verified only by the home company of
+// the client, given by the username in the format <username>@<company>; a username without the company is refused
+// without asking any server, so the password is never sent to a company that isn't the home of the client.
+// The client is represented on this server by a federated client named <username>@<company>, without password,
+// which owns the tickets bought here.
+//
+// Parameters:
+//   - logCred: The credentials given to the login.
+//
+// Return:
+//   - A response with the tokens of a session scoped to the home company, or a 401 Unauthorized
+//     when the username has no company or the home company doesn't accept the credentials.
+func federatedLogin(logCred models.LoginCredentials) models.Response {
+	refused := models.Response{
+		Error:  "invalid credentials",
+		Status: http.StatusUnauthorized,
+	}
+
+	// Sem a companhia de origem, a senha não é enviada a nenhum servidor
+	username, company, explicit := strings.Cut(logCred.Username, "@")
+	if !explicit || username == "" || company == "" {
+		return refused
+	}
+
+	remote, ok := instance.verifyRemoteCredentials(company, username, logCred.Password)
+	if !ok {
+		return refused
+	}
+
+	client, err := federatedClient(*remote, company)
+	if err != nil {
+		return internalError("failed to create federated client")
+	}
+
+	tokens, err := issueTokens(client, company, logCred.Device)
+	if err != nil {
+		return internalError("failed to create token")
+	}
+	tokens["home"] = company
+
+	return models.Response{
+		Data:   tokens,
+		Status: http.StatusOK,
+	}
+}
+
+// federatedClient finds the federated client of a client of another company, creating it on its first login.
+func federatedClient(remote models.FederatedClient, home string) (*models.Client, error) {
+	username := remote.Username + "@" + home
+
+	if client, err := dao.GetClientDAO().FindByUsername(username); err == nil {
+		if client.Name != remote.Name {
+			client.Name = remote.Name
+			dao.GetClientDAO().Update(*client)
+		}
+		return client, nil
+	}
+
+	if err := dao.GetClientDAO().Insert(models.Client{
+		Name:        remote.Name,
+		Username:    username,
+		HomeCompany: home,
+	}); err != nil {
+		return nil, err
+	}
+
+	return dao.GetClientDAO().FindByUsername(username)
+}
+
+// onlineCompanies returns the names of the connected servers that are online, sorted.
+func (s *System) onlineCompanies() []string {
+	s.Lock.RLock()
+	defer s.Lock.RUnlock()
+
+	companies := make([]string, 0, len(s.Connections))
+	for _, conn := range s.Connections {
+		if conn.IsOnline {
+			companies = append(companies, conn.Name)
+		}
+	}
+	sort.Strings(companies)
+	return companies
+}
+
+// verifyRemoteCredentials asks the home company of a client to verify its credentials.
+//
+// Return:
+//   - The client, as given by its home company.
+//   - true if the home company is online and accepted the credentials, false otherwise.
+func (s *System) verifyRemoteCredentials(company string, username string, password string) (*models.FederatedClient, bool) {
+	id, conn := s.FindConnectionByName(company)
+	if id == "" || !conn.IsOnline {
+		return nil, false
+	}
+
+	status, response, err := s.sendServerMessage(id, *conn, http.MethodPost, "/server/federation/credentials",
+		models.CredentialCheck{Username: username, Password: password})
+	if err != nil {
+		log.Printf("Error verifying credentials on %s: %v", company, err)
+		return nil, false
+	}
+	if status != http.StatusOK || response == nil {
+		return nil, false
+	}
+
+	var remote models.FederatedClient
+	if err := decodeBody(response.Body, &remote); err != nil || remote.Username != username {
+		return nil, false
+	}
+	return &remote, true
+}
+
+// federatedTickets lists the tickets a client of this company bought on the other servers through a
+// federated login. The servers that are offline are skipped.
+func (s *System) federatedTickets(username string) []map[string]interface{} {
+	tickets := make([]map[string]interface{}, 0)
+
+	for _, company := range s.onlineCompanies() {
+		id, conn := s.FindConnectionByName(company)
+		if id == "" {
+			continue
+		}
+
+		status, response, err := s.sendServerMessage(id, *conn, http.MethodPost, "/server/federation/tickets",
+			models.FederatedTicketQuery{Username: username, Home: s.ServerName})
+		if err != nil || status != http.StatusOK || response == nil {
+			log.Printf("Federated tickets of %s not listed on %s: %v", username, company, err)
+			continue
+		}
+
+		var remote []map[string]interface{}
+		if err := decodeBody(response.Body, &remote); err != nil {
+			continue
+		}
+		for _, ticket := range remote {
+			// O ID é local ao outro servidor, que é quem pode cancelar o ticket
+			delete(ticket, "ID")
+			ticket["Server"] = company
+			tickets = append(tickets, ticket)
+		}
+	}
+
+	return tickets
+}
+
+// HandleVerifyCredentials handles the credential checks of the federated logins made on other servers.
+// Only the connected servers are answered, and only for the clients of this company.
+func (s *System) HandleVerifyCredentials(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	var check models.CredentialCheck
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil || decodeBody(msg.Body, &check) != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	if !cfg.Federation || !s.isConnection(msg.From) {
+		http.Error(w, "Federation not allowed", http.StatusForbidden)
+		return
+	}
+
+	client, err := dao.GetClientDAO().FindByUsername(check.Username)
+	if err != nil || client.HomeCompany != "" || !passwordMatches(client, check.Password) {
+		s.replyFederation(w, msg.From, http.StatusUnauthorized, nil)
+		return
+	}
+
+	s.replyFederation(w, msg.From, http.StatusOK, models.FederatedClient{Username: client.Username, Name: client.Name})
+}
+
+// HandleFederatedTickets handles the requests of the home company of a client for the tickets it bought on
+// this server through a federated login. Only the home company may list them.
+func (s *System) HandleFederatedTickets(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	var query models.FederatedTicketQuery
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil || decodeBody(msg.Body, &query) != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.RLock()
+	conn, exists := s.Connections[msg.From]
+	s.Lock.RUnlock()
+	if !exists || conn.Name != query.Home {
+		http.Error(w, "Only the home company may list the tickets", http.StatusForbidden)
+		return
+	}
+
+	tickets := make([]map[string]interface{}, 0)
+	if client, err := dao.GetClientDAO().FindByUsername(query.Username + "@" + query.Home); err == nil {
+		if client, err := dao.GetClientDAO().FindById(client.ID); err == nil {
+			for _, ticket := range client.ClientFlights {
+				tickets = append(tickets, ticketView(ticket))
+			}
+		}
+	}
+
+	s.replyFederation(w, msg.From, http.StatusOK, tickets)
+}
+
+func (s *System) isConnection(id string) bool {
+	s.Lock.RLock()
+	defer s.Lock.RUnlock()
+
+	_, exists := s.Connections[id]
+	return exists
+}
+
+func (s *System) replyFederation(w http.ResponseWriter, to string, status int, body interface{}) {
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), to, s.VectorClock, body)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, status)
+}
@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"strconv"
+	"strings"
+)
+
+// handleGetFlights is an HTTP handler function that retrieves flight information based on the provided flight IDs.
+// It checks the HTTP method of the request to ensure it's a POST request.
+// If the method is not POST, it returns a 405 Method Not Allowed status with an error message.
+// It extracts the user's authorization token from the request headers and decodes the request body into a FlightsRequest struct.
+// If the decoding fails, it returns a 400 Bad Request status.
+// It then constructs a Request object with the appropriate action, authorization token, and flight IDs,
+// and sends it to the server using the writeAndReturnResponse function.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleGetFlights(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+
+	var flightIds models.FlightsRequest
+	err := json.NewDecoder(r.Body).Decode(&flightIds)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := Flights(models.Request{
+		Auth: token,
+		Data: flightIds,
+	})
+
+	returnResponse(w, r, response)
+}
+
+// handleGetRoute is an HTTP handler function that retrieves route information based on the provided source and destination.
+// It checks the HTTP method of the request to ensure it's a GET request.
+// If the method is not GET, it returns a 405 Method Not Allowed status with an error message.
+// It extracts the source, destination, search mode, fare class and itinerary filters from the request query parameters and the user's authorization token from the request headers.
+// It then constructs a Request object with the appropriate action, authorization token, and route request data,
+// and sends it to the server using the writeAndReturnResponse function.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleGetRoute(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	queryParams := r.URL.Query()
+
+	routeRequest := models.RouteRequest{
+		Source:    queryParams.Get("src"),
+		Dest:      queryParams.Get("dest"),
+		Mode:      queryParams.Get("mode"),
+		Companies: splitQueryList(queryParams.Get("companies")),
+		Exclude:   splitQueryList(queryParams.Get("exclude")),
+		Date:      queryParams.Get("date"),
+		Class:     queryParams.Get("class"),
+	}
+
+	var err error
+	if routeRequest.K, err = queryCount(queryParams, "k"); err == nil {
+		routeRequest.MaxLegs, err = queryCount(queryParams, "max-legs")
+	}
+	if err != nil {
+		returnResponse(w, r, models.Response{
+			Error:  err.Error(),
+			Status: http.StatusBadRequest,
+		})
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+	response := Route(models.Request{
+		Auth: token,
+		Data: routeRequest,
+	})
+	returnResponse(w, r, response)
+}
+
+// queryCount parses a non-negative integer query parameter, returning 0 if it is absent.
+func queryCount(queryParams url.Values, param string) (int, error) {
+	raw := queryParams.Get(param)
+	if raw == "" {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("not valid %s", param)
+	}
+	return n, nil
+}
+
+// splitQueryList splits a comma-separated query parameter, ignoring empty items.
+func splitQueryList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// AddFlights stores the flights received from another server.
+// A flight already stored is only replaced by a newer version, so an outdated copy can't roll back its seats.
+func AddFlights(flights []models.Flight) {
+	for _, flight := range flights {
+		stored, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+		if err != nil {
+			flight.ID = 0
+			dao.GetFlightDAO().Insert(flight)
+			continue
+		}
+
+		if flight.Version <= stored.Version {
+			continue
+		}
+
+		cancelled := copyFlightState(stored, flight)
+		dao.GetFlightDAO().Update(*stored)
+		if cancelled {
+			rebookTickets(*stored)
+		}
+	}
+}
+
+// copyFlightState copies to a replica the attributes of a flight that its owner may change.
+// It reports whether the received state cancels a flight that was still active in the replica.
+func copyFlightState(local *models.Flight, received models.Flight) bool {
+	cancelled := !local.DeletedAt.Valid && received.DeletedAt.Valid
+	local.Seats = received.Seats
+	local.Capacity = received.Capacity
+	local.Layout = received.Layout
+	local.Occupancy = received.Occupancy
+	local.Fares = received.Fares
+	local.Price = received.Price
+	local.Departure = received.Departure
+	local.Arrival = received.Arrival
+	local.Status = received.Status
+	local.EstimatedDeparture = received.EstimatedDeparture
+	local.Version = received.Version
+	local.VectorClock = received.VectorClock
+	local.DeletedAt = received.DeletedAt
+	return cancelled
+}
+
+func RemoveFlights(company string) {
+	dao.GetFlightDAO().DeleteByCompany(company)
+}
@@ -3,12 +3,14 @@ package server
 import (
 	"bufio"
 	"encoding/json"
-	"giro/internal/models"
 	"log"
 	"net"
 	"net/http"
+	"passcom/internal/models"
+	"strconv"
 	"strings"
 	"syscall"
+	"time"
 )
 
 // allowCrossOrigin is a middleware function that handles Cross-Origin Resource Sharing (CORS)
@@ -21,7 +23,7 @@ import (
 func allowCrossOrigin(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, On-Behalf-Of")
 
 	if r.Method == http.MethodOptions {
 		w.WriteHeader(http.StatusNoContent)
@@ -35,23 +37,23 @@ func returnResponse(w http.ResponseWriter, r *http.Request, responseData models.
 	json.NewEncoder(w).Encode(responseData)
 }
 
-// HandleCLIServer starts a TCP server listening on the specified CLIPORT.
+// HandleCLIServer starts a TCP server listening on the configured CLI port.
 // This server accepts incoming connections and handles them using the handleCLIConnection function.
 // The server logs any errors during initialization, listening, or accepting connections.
 //
 // The function performs the following steps:
-// 1. Listens for incoming TCP connections on the specified CLIPORT.
+// 1. Listens for incoming TCP connections on the configured CLI port.
 // 2. If an error occurs during listening, logs the error and terminates the program.
 // 3. Accepts incoming connections and starts a new goroutine to handle each connection using the handleCLIConnection function.
 // 4. Logs any errors that occur during connection acceptance.
 func (s *System) HandleCLIServer() {
-	listener, err := net.Listen("tcp", CLIPORT)
+	listener, err := net.Listen("tcp", ":"+cfg.CLIPort)
 	if err != nil {
 		log.Fatal("Error initiating server:", err)
 	}
 	defer listener.Close()
 
-	log.Println("See your server working on the TCP CLI server on port", CLIPORT)
+	log.Println("See your server working on the TCP CLI server on port", cfg.CLIPort)
 
 	for {
 		conn, err := listener.Accept()
@@ -96,6 +98,11 @@ func (s *System) handleCLIConnection(conn net.Conn) {
 					"\n- help: to see commands" +
 					"\n- info: to see server informations" +
 					"\n- addconn <address> <port>: to add a new connection" +
+					"\n- escrow <seats>: to grant each connection a quota of seats on each flight (0 disables it)" +
+					"\n- repair [name]: to reconcile the flights with all online connections, or only with the given one" +
+					"\n- schedule [days]: to generate the flights of the schedules for the next days" +
+					"\n- status <flight> <status> [estimated departure]: to change the status of a flight of this company, given its UniqueId" +
+					"\n- role <username> <role>: to change the role of a client (customer, agent, airline-admin or ops)" +
 					"\n- quit: to close the connection" +
 					"\n- shutdown: to shut down the server\n"))
 
@@ -109,9 +116,8 @@ func (s *System) handleCLIConnection(conn net.Conn) {
 				address := args[0]
 				connPort := args[1]
 				conn.Write([]byte("Requesting connection to " + address + ":" + connPort + "...\n"))
-				s.RequestConnection(address, connPort)
-				id, serverConn := s.FindConnectionByName(address)
-				if serverConn == nil {
+				id := s.RequestConnection(address, connPort)
+				if id == "" {
 					conn.Write([]byte("Connection not found.\n"))
 				} else {
 					conn.Write([]byte("Requesting database from " + address + ":" + connPort + "...\n"))
@@ -142,6 +148,90 @@ func (s *System) handleCLIConnection(conn net.Conn) {
 				}
 			}
 
+		case "escrow":
+			if len(args) < 1 {
+				conn.Write([]byte("Error: 'escrow' requires one argument (seats).\n"))
+			} else if seats, err := strconv.Atoi(args[0]); err != nil || seats < 0 {
+				conn.Write([]byte("Error: seats must be a non-negative number.\n"))
+			} else {
+				s.Lock.Lock()
+				s.EscrowQuota = seats
+				s.Lock.Unlock()
+				conn.Write([]byte("Rebalancing quotas...\n"))
+				conn.Write([]byte(s.rebalanceAllQuotas()))
+			}
+
+		case "repair":
+			if len(args) > 0 {
+				id, serverConn := s.FindConnectionByName(args[0])
+				if serverConn == nil {
+					conn.Write([]byte("Connection not found.\n"))
+				} else if !serverConn.IsOnline {
+					conn.Write([]byte("Connection " + serverConn.Name + " is offline.\n"))
+				} else if report, err := s.antiEntropy(id, *serverConn); err != nil {
+					conn.Write([]byte("Error: " + err.Error() + "\n"))
+				} else {
+					conn.Write([]byte(report.String()))
+				}
+			} else {
+				reports := s.runAntiEntropy()
+				if len(reports) == 0 {
+					conn.Write([]byte("No online connections.\n"))
+				}
+				for _, report := range reports {
+					conn.Write([]byte(report.String()))
+				}
+			}
+
+		case "schedule":
+			days := cfg.ScheduleDays
+			if len(args) > 0 {
+				days, _ = strconv.Atoi(args[0])
+			}
+			if days <= 0 {
+				conn.Write([]byte("Error: days must be a positive number.\n"))
+			} else {
+				created := s.generateSchedules(days)
+				conn.Write([]byte(strconv.Itoa(created) + " flights generated for the next " + strconv.Itoa(days) + " days.\n"))
+			}
+
+		case "status":
+			if len(args) < 2 {
+				conn.Write([]byte("Error: 'status' requires two arguments (flight, status).\n"))
+				break
+			}
+
+			change := models.FlightStatusChange{Status: args[1]}
+			if len(args) > 2 {
+				estimated, err := time.Parse(time.RFC3339, args[2])
+				if err != nil {
+					conn.Write([]byte("Error: the estimated departure must be in RFC 3339 format (2006-01-02T15:04:05-03:00).\n"))
+					break
+				}
+				change.EstimatedDeparture = &estimated
+			}
+
+			if response := s.SetFlightStatus(args[0], change); response.Error != "" {
+				conn.Write([]byte("Error: " + response.Error + ".\n"))
+			} else {
+				conn.Write([]byte("Flight " + args[0] + " is now " + change.Status + ".\n"))
+			}
+
+		case "role":
+			if len(args) < 2 {
+				conn.Write([]byte("Error: 'role' requires two arguments (username, role).\n"))
+				break
+			}
+
+			response := SetRole(models.RoleChange{Username: args[0], Role: args[1]})
+			auditCommand(input, args[0], response.Status)
+
+			if response.Error != "" {
+				conn.Write([]byte("Error: " + response.Error + ".\n"))
+			} else {
+				conn.Write([]byte("Client " + args[0] + " is now " + args[1] + ".\n"))
+			}
+
 		case "quit":
 			conn.Write([]byte("Closing CLI...\n"))
 			return
@@ -1,12 +1,12 @@
 package server
 
 import (
-	"boreal/internal/models"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"passcom/internal/models"
 	"time"
 )
 
@@ -34,8 +34,9 @@ func (s *System) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
 	// Atualiza o VectorClock com base no *heartbeat* recebido
 	s.UpdateClock(receivedMessage.VectorClock)
 
-	// Cria uma nova mensagem de resposta com o VectorClock atualizado
-	responseMessage, err := models.CreateMessage(s.ServerId.String(), receivedMessage.From, s.VectorClock, "Healthy")
+	// Cria uma nova mensagem de resposta com o VectorClock atualizado e o último valor do relógio gravado em um voo próprio
+	responseMessage, err := models.CreateMessage(s.ServerId.String(), receivedMessage.From, s.VectorClock,
+		models.Heartbeat{Status: "Healthy", Changes: s.flightChanges()})
 
 	if err != nil {
 		log.Printf("Error creating heartbeat response message: %v", err)
@@ -73,19 +74,28 @@ func (s *System) sendHeartbeats() {
 // Função para enviar um heartbeat a uma única conexão e atualizar o status
 func (s *System) sendHeartbeatToConnection(id string, conn models.Connection) {
 	defer s.wg.Done()
+	s.SendHeartbeat(id, conn)
+}
 
+// SendHeartbeat sends a heartbeat to a connection and updates its status. When the connection comes back
+// online, the broadcasts queued in its outbox are redelivered; when it reports flight changes after the last
+// synchronized value of its clock entry, the missed changes are pulled by catchUp.
+//
+// Return:
+//   - true if the connection is online, false otherwise.
+func (s *System) SendHeartbeat(id string, conn models.Connection) bool {
 	heartbeat, err := models.CreateMessage(s.ServerId.String(), id, s.VectorClock, "Heartbeat")
 
 	if err != nil {
 		log.Printf("Error creating heartbeat message: %v", err)
-		return
+		return false
 	}
 
 	// Serializar a mensagem de heartbeat
 	jsonData, err := json.Marshal(heartbeat)
 	if err != nil {
 		log.Printf("Error encoding heartbeat message: %v", err)
-		return
+		return false
 	}
 
 	// Construir a URL com endereço e porta
@@ -94,7 +104,7 @@ func (s *System) sendHeartbeatToConnection(id string, conn models.Connection) {
 	if err != nil {
 		log.Printf("Error creating heartbeat request: %v", err)
 		s.UpdateConnectionStatus(id, false)
-		return
+		return false
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -109,7 +119,33 @@ func (s *System) sendHeartbeatToConnection(id string, conn models.Connection) {
 	}
 	s.UpdateConnectionStatus(id, online)
 
+	// A conexão voltou a ficar online: reenvia os broadcasts pendentes
+	if online && !conn.IsOnline {
+		s.flushOutbox(id)
+	}
+
 	if resp != nil {
-		resp.Body.Close()
+		defer resp.Body.Close()
+	}
+
+	if !online {
+		return false
+	}
+
+	var reply models.Message
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		log.Printf("Error decoding heartbeat response: %v", err)
+		return true
+	}
+
+	s.Lock.Lock()
+	s.UpdateClock(reply.VectorClock)
+	s.Lock.Unlock()
+
+	// Se a conexão alterou um voo depois do último valor sincronizado, busca as alterações perdidas
+	var beat models.Heartbeat
+	if decodeBody(reply.Body, &beat) == nil && beat.Changes > conn.SyncedClock {
+		s.catchUp(id, conn)
 	}
+	return true
 }
@@ -0,0 +1,702 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var errSeatsUnavailable = errors.New("not available seats")
+
+func handleHold(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	switch r.Method {
+	case http.MethodGet:
+		handleGetHolds(w, r)
+	case http.MethodPost:
+		handleCreateHold(w, r)
+	case http.MethodDelete:
+		handleReleaseHold(w, r)
+	default:
+		http.Error(w, "only GET, POST, DELETE allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+func handleGetHolds(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	response := GetHolds(models.Request{
+		Auth: token,
+	})
+	returnResponse(w, r, response)
+}
+
+func handleCreateHold(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+
+	var holdRequest models.HoldRequest
+	err := json.NewDecoder(r.Body).Decode(&holdRequest)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := HoldSeats(models.Request{
+		Auth: token,
+		Data: holdRequest,
+	})
+	returnResponse(w, r, response)
+}
+
+func handleReleaseHold(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	id := r.URL.Query().Get("id")
+
+	response := ReleaseHold(id, models.Request{
+		Auth: token,
+	})
+	returnResponse(w, r, response)
+}
+
+// handleCheckout is a HTTP handler function that handles requests for converting a hold into tickets.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleCheckout(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+	var checkout models.Checkout
+
+	err := json.NewDecoder(r.Body).Decode(&checkout)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := CheckoutHold(models.Request{
+		Auth: token,
+		Data: checkout,
+	})
+	returnResponse(w, r, response)
+}
+
+// HoldSeats reserves seats of a fare class of a flight for an authenticated client until the checkout or the end of the TTL.
+// The seats are taken from the flight by its owner, so they stop being offered by every server.
+//
+// Parameters:
+//   - request: The request containing the authentication token and the HoldRequest data.
+//
+// Return:
+//   - A models.Response with the created hold, or the reason why it couldn't be created.
+func HoldSeats(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	var holdRequest models.HoldRequest
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &holdRequest)
+
+	if holdRequest.Seats <= 0 {
+		return models.Response{
+			Error:  "invalid number of seats",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	flight, err := dao.GetFlightDAO().FindById(holdRequest.FlightId)
+	if err != nil {
+		return models.Response{
+			Error:  "flight not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	fare, exists := flight.Fare(holdRequest.Class)
+	if !exists {
+		return models.Response{
+			Error:  "fare class not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	ttl := HOLD_DEFAULT_TTL
+	if holdRequest.TTL > 0 {
+		ttl = min(time.Duration(holdRequest.TTL)*time.Second, HOLD_MAX_TTL)
+	}
+
+	hold := models.Hold{
+		HoldId:    uuid.New().String(),
+		FlightId:  flight.UniqueId,
+		ClientId:  session.ClientID,
+		Seats:     holdRequest.Seats,
+		Class:     fare.Class,
+		Status:    models.PENDING,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	if err := instance.createHold(*flight, &hold); err != nil {
+		return models.Response{
+			Error:  err.Error(),
+			Status: http.StatusNotAcceptable,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"hold": hold,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// GetHolds returns the holds of the authenticated client.
+func GetHolds(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	holds, err := dao.GetHoldDAO().FindByClient(session.ClientID)
+	if err != nil {
+		return models.Response{
+			Error:  "failed to find holds",
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"holds": holds,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// ReleaseHold gives back the seats of a hold of the authenticated client before it expires.
+func ReleaseHold(holdId string, request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	hold, err := dao.GetHoldDAO().FindByHoldId(holdId)
+	if err != nil || hold.ClientId != session.ClientID {
+		return models.Response{
+			Error:  "hold not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if hold.Status != models.PENDING {
+		return models.Response{
+			Error:  "hold is not active",
+			Status: http.StatusConflict,
+		}
+	}
+
+	instance.releaseHold(*hold)
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg": "success",
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// CheckoutHold converts a hold of the authenticated client into one ticket per held seat.
+// The owner of the flight must confirm the checkout before the hold expires. Only one of concurrent checkouts
+// of the same hold creates the tickets; the others get 409 Conflict.
+func CheckoutHold(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	var checkout models.Checkout
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &checkout)
+
+	hold, err := dao.GetHoldDAO().FindByHoldId(checkout.HoldId)
+	if err != nil || hold.ClientId != session.ClientID {
+		return models.Response{
+			Error:  "hold not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if hold.Status != models.PENDING || time.Now().After(hold.ExpiresAt) {
+		return models.Response{
+			Error:  "hold is not active",
+			Status: http.StatusConflict,
+		}
+	}
+
+	seats, confirmed := instance.checkoutHold(*hold)
+	if !confirmed {
+		return models.Response{
+			Error:  "hold expired",
+			Status: http.StatusConflict,
+		}
+	}
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(hold.FlightId)
+	if err != nil {
+		return models.Response{
+			Error:  "flight not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	tickets := make([]models.Ticket, hold.Seats)
+	for i := range tickets {
+		tickets[i] = models.Ticket{
+			ClientId: session.ClientID,
+			FlightId: flight.ID,
+			Class:    hold.Class,
+			Price:    hold.Price,
+		}
+		if i < len(seats) {
+			tickets[i].Seat = seats[i]
+		}
+	}
+	if err := dao.GetTicketDAO().InsertAll(tickets); err != nil {
+		return models.Response{
+			Error:  "failed to create tickets",
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg": "success",
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// createHold reserves the seats of a hold on the owner of the flight, which quotes their price, and stores
+// the hold locally.
+//
+// Return:
+//   - An error if the seats couldn't be reserved.
+func (s *System) createHold(flight models.Flight, hold *models.Hold) error {
+	if flight.Company == s.ServerName {
+		hold.Owner = s.ServerId.String()
+
+		s.Lock.Lock()
+		reserved, err := s.reserveHold(hold)
+		if err == nil {
+			if err = dao.GetHoldDAO().Insert(*hold); err != nil {
+				reserved = s.restoreHold(*hold)
+			}
+		}
+		s.Lock.Unlock()
+
+		if reserved != nil {
+			s.broadcast(*reserved)
+		}
+		return err
+	}
+
+	id, conn := s.FindConnectionByName(flight.Company)
+	if id == "" || !conn.IsOnline {
+		return errors.New("flight owner is unreachable")
+	}
+	hold.Owner = id
+
+	status, response, err := s.sendServerMessage(id, *conn, http.MethodPost, "/server/hold", *hold)
+	if err != nil {
+		return errors.New("flight owner is unreachable")
+	}
+	if status != http.StatusOK {
+		return errSeatsUnavailable
+	}
+
+	// O preço cotado pelo dono fica travado na reserva
+	var reserved models.Hold
+	if response == nil || decodeBody(response.Body, &reserved) != nil {
+		return errors.New("flight owner didn't quote the seats")
+	}
+	hold.Price = reserved.Price
+
+	// Se a reserva não puder ser gravada, o dono a libera quando expirar
+	return dao.GetHoldDAO().Insert(*hold)
+}
+
+// releaseHold gives back the seats of an active hold to its owner and marks it as released.
+// If the owner is unreachable, it releases the hold by itself when it expires.
+func (s *System) releaseHold(hold models.Hold) {
+	if hold.Owner == s.ServerId.String() {
+		s.Lock.Lock()
+		released := s.restoreHold(hold)
+		hold.Status = models.REJECTED
+		dao.GetHoldDAO().Update(hold)
+		s.Lock.Unlock()
+
+		if released != nil {
+			s.broadcast(*released)
+		}
+		return
+	}
+
+	hold.Status = models.REJECTED
+	dao.GetHoldDAO().Update(hold)
+
+	s.Lock.RLock()
+	conn, exists := s.Connections[hold.Owner]
+	s.Lock.RUnlock()
+
+	if exists && conn.IsOnline {
+		if _, _, err := s.sendServerMessage(hold.Owner, conn, http.MethodPost, "/server/hold/release", hold); err != nil {
+			log.Printf("Error releasing hold %s on %s: %v", hold.HoldId, conn.Name, err)
+		}
+	}
+}
+
+// checkoutHold confirms the checkout of an active hold on the owner of its flight.
+//
+// Return:
+//   - The seats assigned by the owner to the hold.
+//   - true if the owner confirmed the checkout before the hold expired and no other checkout of the hold
+//     did it first, false otherwise.
+func (s *System) checkoutHold(hold models.Hold) ([]string, bool) {
+	if hold.Owner == s.ServerId.String() {
+		s.Lock.Lock()
+		stored, err := dao.GetHoldDAO().FindByHoldId(hold.HoldId)
+		var assigned *models.Flight
+		confirmed := err == nil
+		if confirmed {
+			assigned, confirmed = s.confirmHold(stored)
+		}
+		s.Lock.Unlock()
+
+		if assigned != nil {
+			s.broadcast(*assigned)
+		}
+		if !confirmed {
+			return nil, false
+		}
+		return stored.SeatNumbers, true
+	}
+
+	s.Lock.RLock()
+	conn, exists := s.Connections[hold.Owner]
+	s.Lock.RUnlock()
+
+	if !exists || !conn.IsOnline {
+		return nil, false
+	}
+
+	// A reserva local passa para COMMITED antes da confirmação do dono, então um checkout concorrente é recusado
+	if claimed, err := dao.GetHoldDAO().UpdateStatus(hold.HoldId, models.PENDING, models.COMMITED); err != nil || !claimed {
+		return nil, false
+	}
+
+	status, response, err := s.sendServerMessage(hold.Owner, conn, http.MethodPost, "/server/hold/checkout", hold)
+	if err != nil || status != http.StatusOK {
+		dao.GetHoldDAO().UpdateStatus(hold.HoldId, models.COMMITED, models.PENDING)
+		return nil, false
+	}
+
+	var confirmed models.Hold
+	if response != nil && decodeBody(response.Body, &confirmed) == nil {
+		hold.SeatNumbers = confirmed.SeatNumbers
+	}
+	hold.Status = models.COMMITED
+	dao.GetHoldDAO().Update(hold)
+	return hold.SeatNumbers, true
+}
+
+// reserveHold takes the seats of a hold from its fare class of a flight of this server and locks into the hold
+// the price quoted for each seat. The caller must hold the system lock.
+//
+// Return:
+//   - The updated flight, to be broadcasted after the lock is released.
+//   - An error if the fare class doesn't have enough available seats.
+func (s *System) reserveHold(hold *models.Hold) (*models.Flight, error) {
+	flight, err := dao.GetFlightDAO().FindByUniqueId(hold.FlightId)
+	if err != nil || flight.Company != s.ServerName {
+		return nil, errors.New("flight not found")
+	}
+
+	price := quote(*flight, hold.Class)
+	if _, err := flight.TakeSeats(hold.Class, hold.Seats); err != nil {
+		return nil, errSeatsUnavailable
+	}
+	hold.Price = price
+
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+	return flight, nil
+}
+
+// restoreHold gives back the seats of a hold to a flight of this server. The caller must hold the system lock.
+//
+// Return:
+//   - The updated flight, to be broadcasted after the lock is released, or nil if it wasn't found.
+func (s *System) restoreHold(hold models.Hold) *models.Flight {
+	flight, err := dao.GetFlightDAO().FindByUniqueId(hold.FlightId)
+	if err != nil {
+		return nil
+	}
+
+	flight.ReturnSeats(hold.Class, hold.Seats)
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+	return flight
+}
+
+// confirmHold marks a hold stored by the owner of the flight as checked out, if it's still active, and assigns
+// a seat of the flight to each held seat. The hold changes from PENDING to COMMITED only once, so a hold already
+// checked out isn't confirmed again. The caller must hold the system lock.
+//
+// Return:
+//   - The flight with the assigned seats, to be broadcasted after the lock is released, or nil.
+//   - true if the hold was checked out by this call, false otherwise.
+func (s *System) confirmHold(hold *models.Hold) (*models.Flight, bool) {
+	if time.Now().After(hold.ExpiresAt) {
+		return nil, false
+	}
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(hold.FlightId)
+	if err != nil {
+		return nil, false
+	}
+
+	if claimed, err := dao.GetHoldDAO().UpdateStatus(hold.HoldId, models.PENDING, models.COMMITED); err != nil || !claimed {
+		return nil, false
+	}
+	hold.Status = models.COMMITED
+
+	// Os assentos já foram retirados do voo na reserva; o checkout apenas os atribui
+	for i := 0; i < hold.Seats; i++ {
+		seat, err := flight.AssignSeat("", hold.Class)
+		if err != nil {
+			break
+		}
+		hold.SeatNumbers = append(hold.SeatNumbers, seat)
+	}
+
+	if dao.GetHoldDAO().Update(*hold) != nil {
+		return nil, false
+	}
+
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+	return flight, true
+}
+
+// HandleServerHold reserves on this server the seats of a hold created by a client of another server, replying
+// with the hold and the price quoted for its seats.
+func (s *System) HandleServerHold(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, hold, err := decodeHoldMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+
+	// Uma reserva retransmitida mantém o resultado e o preço da primeira requisição
+	if stored, err := dao.GetHoldDAO().FindByHoldId(hold.HoldId); err == nil {
+		s.Lock.Unlock()
+		s.replyHold(w, msg.From, stored.Status == models.PENDING, stored)
+		return
+	}
+
+	hold.ID = 0
+	hold.ClientId = 0
+	hold.Owner = s.ServerId.String()
+	hold.Status = models.PENDING
+
+	reserved, err := s.reserveHold(hold)
+	if err == nil {
+		if err = dao.GetHoldDAO().Insert(*hold); err != nil {
+			reserved = s.restoreHold(*hold)
+		}
+	}
+	s.Lock.Unlock()
+
+	if reserved != nil {
+		s.broadcast(*reserved)
+	}
+
+	s.replyHold(w, msg.From, err == nil, hold)
+}
+
+// HandleHoldCheckout confirms the checkout of a hold on a flight of this server. Confirming an already checked out
+// hold succeeds, so the request can be retried.
+func (s *System) HandleHoldCheckout(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, hold, err := decodeHoldMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	var assigned *models.Flight
+	stored, err := dao.GetHoldDAO().FindByHoldId(hold.HoldId)
+	confirmed := err == nil
+	if confirmed && stored.Status != models.COMMITED {
+		assigned, confirmed = s.confirmHold(stored)
+	}
+	s.Lock.Unlock()
+
+	if assigned != nil {
+		s.broadcast(*assigned)
+	}
+
+	// Um checkout já confirmado é respondido com sucesso, para que o servidor do cliente possa repetir a requisição.
+	// A resposta leva os assentos atribuídos à reserva
+	s.replyHold(w, msg.From, confirmed, stored)
+}
+
+// HandleHoldRelease gives back the seats of a hold on a flight of this server before it expires.
+func (s *System) HandleHoldRelease(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, hold, err := decodeHoldMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	var released *models.Flight
+	stored, err := dao.GetHoldDAO().FindByHoldId(hold.HoldId)
+	if err == nil && stored.Status == models.PENDING {
+		released = s.restoreHold(*stored)
+		stored.Status = models.REJECTED
+		dao.GetHoldDAO().Update(*stored)
+	}
+	s.Lock.Unlock()
+
+	if released != nil {
+		s.broadcast(*released)
+	}
+
+	s.replyHold(w, msg.From, err == nil && stored.Status == models.REJECTED, "")
+}
+
+// CleanupHolds periodically releases the expired holds, similar to CleanupSessions.
+func (s *System) CleanupHolds() {
+	ticker := time.NewTicker(HOLD_SWEEP_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.ReleaseExpiredHolds()
+	}
+}
+
+// ReleaseExpiredHolds releases the holds whose TTL has ended. The seats of the holds on flights of this server
+// are given back; the holds on flights of other servers are only marked as released, since their owners release
+// the seats by themselves.
+func (s *System) ReleaseExpiredHolds() {
+	holds, err := dao.GetHoldDAO().FindExpired(time.Now())
+	if err != nil {
+		return
+	}
+
+	released := make([]models.Flight, 0)
+	s.Lock.Lock()
+	for _, hold := range holds {
+		if hold.Owner == s.ServerId.String() {
+			if flight := s.restoreHold(hold); flight != nil {
+				released = append(released, *flight)
+			}
+		}
+		hold.Status = models.REJECTED
+		dao.GetHoldDAO().Update(hold)
+		log.Printf("Hold %s expired", hold.HoldId)
+	}
+	s.Lock.Unlock()
+
+	for _, flight := range released {
+		s.broadcast(flight)
+	}
+}
+
+func (s *System) replyHold(w http.ResponseWriter, to string, success bool, body interface{}) {
+	status := http.StatusOK
+	if !success {
+		status = http.StatusConflict
+	}
+
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), to, s.VectorClock, body)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, status)
+}
+
+// decodeHoldMessage decodes a message whose body is a models.Hold.
+func decodeHoldMessage(r *http.Request) (*models.Message, *models.Hold, error) {
+	var msg models.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		return nil, nil, err
+	}
+
+	var hold models.Hold
+	if err := decodeBody(msg.Body, &hold); err != nil {
+		return nil, nil, err
+	}
+
+	if hold.HoldId == "" {
+		return nil, nil, errors.New("missing hold ID")
+	}
+
+	return &msg, &hold, nil
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"sync"
+	"time"
+)
+
+const IDEMPOTENCY_HEADER = "Idempotency-Key"
+
+// responseRecorder captures the response written by a handler, so it can be stored before being sent.
+type responseRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header { return r.header }
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(data)
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// withIdempotency runs handler at most once for the given key within IDEMPOTENCY_WINDOW.
+// The response of the first execution is stored and replayed to every retry with the same key;
+// concurrent retries wait for the first execution to finish. Only final outcomes are stored (see models.FinalStatus),
+// so a retry of a request that failed for a condition that may change, such as an offline owner, is executed again.
+// The hash of the request is stored with its response, and a key reused with a different request is rejected with
+// 422 Unprocessable Entity. An empty key disables the deduplication.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the response.
+//   - key: The key identifying the request, such as a message ID or a client idempotency key.
+//   - request: The hash of the data of the request (see requestHash).
+//   - handler: The function that handles the request, writing its response.
+func (s *System) withIdempotency(w http.ResponseWriter, key string, request string, handler func(w http.ResponseWriter)) {
+	if key == "" {
+		handler(w)
+		return
+	}
+
+	lock := s.lockInflight(key)
+	defer func() {
+		// Retentativas posteriores encontram a resposta armazenada, ou executam a requisição novamente
+		s.inflight.Delete(key)
+		lock.Unlock()
+	}()
+
+	// A resposta é procurada somente com o mutex da chave, depois que a execução anterior terminou
+	if record, err := dao.GetIdempotencyDAO().FindByKey(key, time.Now()); err == nil {
+		if record.RequestHash != request {
+			log.Printf("Rejecting request %s: key reused with a different request", key)
+			returnResponse(w, nil, models.Response{
+				Error:  "idempotency key already used with a different request",
+				Code:   models.CodeIdempotencyKeyReused,
+				Status: http.StatusUnprocessableEntity,
+			})
+			return
+		}
+
+		log.Printf("Replaying stored response of request %s", key)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(record.Status)
+		w.Write([]byte(record.Body))
+		return
+	}
+
+	recorder := &responseRecorder{header: w.Header()}
+	handler(recorder)
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+
+	if models.FinalStatus(recorder.status) {
+		dao.GetIdempotencyDAO().Insert(models.IdempotencyRecord{
+			Key:         key,
+			RequestHash: request,
+			Status:      recorder.status,
+			Body:        recorder.body.String(),
+			ExpiresAt:   time.Now().Add(IDEMPOTENCY_WINDOW),
+		})
+	}
+
+	w.WriteHeader(recorder.status)
+	w.Write(recorder.body.Bytes())
+}
+
+// lockInflight locks the mutex of a key in execution, creating it if needed. The mutex is removed when the
+// execution ends, so a request that was waiting for it retries with the mutex stored for the key at that time,
+// and two requests with the same key never run at once.
+func (s *System) lockInflight(key string) *sync.Mutex {
+	for {
+		stored, _ := s.inflight.LoadOrStore(key, &sync.Mutex{})
+		lock := stored.(*sync.Mutex)
+		lock.Lock()
+
+		if current, exists := s.inflight.Load(key); exists && current == lock {
+			return lock
+		}
+		lock.Unlock()
+	}
+}
+
+// requestHash returns the hash of the data of a request, stored with its idempotency key.
+func requestHash(data ...interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return utils.HashString(string(jsonData))
+}
+
+// messageKey returns the idempotency key of a message sent by another server, based on its UUIDv7 ID.
+func messageKey(msg models.Message) string {
+	if msg.Id == "" {
+		return ""
+	}
+	return "message:" + msg.Id
+}
+
+// withClientIdempotency deduplicates a client request carrying the Idempotency-Key header.
+// The key is scoped by the authenticated client and by the method and path of the request,
+// so different clients or operations never share a stored response. The request is identified by its query
+// and its decoded body, so the same key can't be reused with other data. Requests without the header,
+// or from clients not authenticated, are handled normally.
+func withClientIdempotency(w http.ResponseWriter, r *http.Request, body interface{}, handler func(w http.ResponseWriter)) {
+	key := r.Header.Get(IDEMPOTENCY_HEADER)
+	session, exists := SessionIfExists(r.Header.Get("Authorization"))
+	if key == "" || !exists {
+		handler(w)
+		return
+	}
+
+	instance.withIdempotency(w, fmt.Sprintf("client:%d:%s:%s:%s", session.ClientID, r.Method, r.URL.Path, key),
+		requestHash(r.URL.RawQuery, body), handler)
+}
+
+// CleanupIdempotencyRecords periodically removes the stored responses whose window expired.
+func (s *System) CleanupIdempotencyRecords() {
+	ticker := time.NewTicker(IDEMPOTENCY_SWEEP_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		dao.GetIdempotencyDAO().DeleteExpired(time.Now())
+	}
+}
@@ -0,0 +1,107 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+)
+
+// handleItinerary is a HTTP handler function that handles requests for buying a whole itinerary.
+// It extracts the user's authorization token from the request headers and decodes the request body into a BuyItinerary struct.
+// If the method is not POST, it returns a 405 Method Not Allowed status with an error message.
+// If the decoding fails, it returns a 400 Bad Request status.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleItinerary(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+	var buyItinerary models.BuyItinerary
+
+	err := json.NewDecoder(r.Body).Decode(&buyItinerary)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response := BuyItinerary(models.Request{
+		Auth: token,
+		Data: buyItinerary,
+	})
+
+	returnResponse(w, r, response)
+}
+
+// BuyItinerary handles the purchase of every leg of a route for an authenticated client.
+// The legs, usually taken from a /route result, must be connected in order: each flight must depart
+// from the airport where the previous one arrives. The legs may belong to different companies and
+// are bought atomically: either a ticket is created for every leg, or no seat is taken.
+//
+// Parameters:
+//   - request: The request containing the authentication token and the BuyItinerary data.
+//
+// Return:
+//   - A models.Response indicating success, or the reason why the itinerary couldn't be bought.
+func BuyItinerary(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	var buyItinerary models.BuyItinerary
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &buyItinerary)
+
+	if len(buyItinerary.FlightIds) == 0 {
+		return models.Response{
+			Error:  "empty itinerary",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	flights := make([]models.Flight, len(buyItinerary.FlightIds))
+	for i, id := range buyItinerary.FlightIds {
+		flight, err := dao.GetFlightDAO().FindById(id)
+		if err != nil {
+			return models.Response{
+				Error:  "flight not found",
+				Status: http.StatusNotFound,
+			}
+		}
+
+		if i > 0 && flights[i-1].DestinationAirportID != flight.OriginAirportID {
+			return models.Response{
+				Error:  "itinerary legs are not connected",
+				Status: http.StatusBadRequest,
+			}
+		}
+		flights[i] = *flight
+	}
+
+	if err := instance.initiateItinerary(flights, buyItinerary.Seats, buyItinerary.Classes, session.ClientID); err != nil {
+		return models.Response{
+			Error:  err.Error(),
+			Status: http.StatusNotAcceptable,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg": "success",
+		},
+		Status: http.StatusOK,
+	}
+}
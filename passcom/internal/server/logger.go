@@ -1,7 +1,7 @@
 package server
 
 import (
-	"rumos/internal/models"
+	"passcom/internal/models"
 	"time"
 )
 
@@ -2,11 +2,12 @@ package server
 
 import (
 	"encoding/json"
-	"fmt"
-	"giro/internal/dao"
-	"giro/internal/models"
+	"log"
 	"net/http"
-	"time"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"strings"
 )
 
 // handleGetUser is an HTTP handler function that retrieves user information.
@@ -79,12 +80,16 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
+	if logCred.Device == "" {
+		logCred.Device = r.UserAgent()
+	}
 
 	responseData := Login(logCred)
 	returnResponse(w, r, responseData)
 }
 
-// passwordMatches checks if the provided password matches the password stored in the client's record.
+// passwordMatches checks if the provided password matches the password hash stored in the client's record.
+// A matching password still stored in plain text, or hashed with older parameters, is replaced by a new hash.
 //
 // Parameters:
 // - client: A pointer to a models.Client representing the user for whom the password needs to be checked.
@@ -94,18 +99,27 @@ func handleLogin(w http.ResponseWriter, r *http.Request) {
 //   - A boolean value indicating whether the provided password matches the password stored in the client's record.
 //     Returns true if the passwords match, false otherwise.
 func passwordMatches(client *models.Client, password string) bool {
-	return client.Password == password
+	match, upgrade := utils.VerifyPassword(client.Password, password)
+
+	if upgrade {
+		// A falha ao atualizar o hash não impede o login; a senha é atualizada no próximo
+		if hash, err := utils.HashPassword(password); err == nil {
+			client.Password = hash
+			if err := dao.GetClientDAO().Update(*client); err != nil {
+				log.Printf("Password of client %d not upgraded: %v", client.ID, err)
+			}
+		}
+	}
+
+	return match
 }
 
 // login handles the login process for a user.
-// It receives a data interface and a connection to the client.
-// It first unmarshals the data into a LoginCredentials struct, then retrieves the client from the database using the provided username.
-// If the client is not found, it sends an error response to the client and returns.
-// If the client is found, it checks if the provided password matches the client's password.
-// If the passwords match, it checks if the client is already logged in by searching for an active session.
-// If the client is already logged in, it sends an error response to the client and returns.
-// If the client is not logged in, it creates a new session for the client, stores it in the database, and sends a success response with the session token to the client.
-// If the passwords do not match, it sends an error response to the client.
+// It receives a data interface, unmarshals it into a LoginCredentials struct and retrieves the client from the database using the provided username.
+// If the client is not found or the password doesn't match, it returns an error response.
+// Otherwise it logs the device in, returning a signed access token and the refresh token of the device.
+// In federation mode, the clients of other companies are logged in by federatedLogin.
+// A client may be logged in on several devices at the same time, up to the configured limit.
 func Login(data interface{}) models.Response {
 	var logCred models.LoginCredentials
 
@@ -114,33 +128,32 @@ func Login(data interface{}) models.Response {
 	jsonData, _ := json.Marshal(data)
 	json.Unmarshal(jsonData, &logCred)
 
+	// O nome de usuário pode indicar a companhia de origem, inclusive a própria
+	if username, home, found := strings.Cut(logCred.Username, "@"); found && home == cfg.Company {
+		logCred.Username = username
+	}
+
 	login, err := dao.GetClientDAO().FindByUsername(logCred.Username)
 
-	if err != nil {
+	// Clientes desconhecidos e clientes federados são verificados pela companhia de origem
+	if cfg.Federation && (err != nil || login.HomeCompany != "") {
+		return federatedLogin(logCred)
+	}
+
+	if err != nil || login.HomeCompany != "" {
 		return models.Response{
 			Error:  "client not found",
 			Status: http.StatusUnauthorized,
 		}
 	}
 
-	var session *models.Session
-
 	if passwordMatches(login, logCred.Password) {
-
-		if s := findUser(login); s != nil {
-			return models.Response{
-				Error:  "more than one user logged",
-				Status: http.StatusUnauthorized,
-			}
-
-		} else {
-			session = &models.Session{ClientID: login.ID, LastTimeActive: time.Now()}
-			dao.GetSessionDAO().Insert(session)
+		tokens, err := issueTokens(login, "", logCred.Device)
+		if err != nil {
+			return internalError("failed to create token")
 		}
 
-		token := session.ID.String()
-
-		response.Data["token"] = token
+		response.Data = tokens
 		response.Status = http.StatusOK
 
 	} else {
@@ -150,30 +163,9 @@ func Login(data interface{}) models.Response {
 	return response
 }
 
-// findUser searches for an active session associated with a given client.
-// It iterates through all sessions stored in the database and checks if the client's ID matches the session's ClientID.
-// If a matching session is found, it is returned. Otherwise, nil is returned.
-//
-// Parameters:
-// - login: A pointer to a models.Client representing the client for which the session needs to be found.
-//
-// Return:
-//   - A pointer to a models.Session representing the active session associated with the given client.
-//     If no matching session is found, nil is returned.
-func findUser(login *models.Client) *models.Session {
-	fmt.Println(dao.GetSessionDAO().FindAll())
-	for _, s := range dao.GetSessionDAO().FindAll() {
-		if s.ClientID == login.ID {
-			return s
-		}
-	}
-	return nil
-}
-
 // logout handles the logout process for a user.
-// It closes the connection, prepares a response object, and checks if a session exists for the given authentication token.
-// If the session is found, it deletes the session from the database and calls the removeReservations function to release reserved seats.
-// Finally, it sends a success message in the response and writes it to the provided connection.
+// It checks if the given access token is valid and, if it is, revokes the refresh token of its device,
+// so no new access tokens are given to it. The access token itself stays valid until it expires.
 //
 // Parameters:
 // - auth: A string representing the authentication token.
@@ -192,7 +184,9 @@ func Logout(req models.Request) models.Response {
 		return response
 	}
 
-	dao.GetSessionDAO().Delete(session)
+	if token, err := dao.GetRefreshTokenDAO().FindByTokenId(session.ID.String()); err == nil {
+		revokeToken(*token)
+	}
 
 	response.Data["msg"] = "logout successfully made"
 	response.Status = http.StatusOK
@@ -236,6 +230,8 @@ func GetUserBySessionToken(request models.Request) models.Response {
 		"Name":          client.Name,
 		"ClientFlights": client.ClientFlights,
 		"Username":      client.Username,
+		"HomeCompany":   client.HomeCompany,
+		"Role":          client.EffectiveRole(),
 	}
 	response.Status = http.StatusOK
 	return response
@@ -0,0 +1,220 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"strings"
+	"time"
+)
+
+// enqueueBroadcast stores the broadcast of a flight in the outbox of a connection.
+// Queued broadcasts of the same flight superseded by the new state are dropped; if a queued broadcast
+// is newer than the new state, the new state is not queued.
+//
+// Return:
+//   - The stored outbox message, or nil if the new state was already superseded.
+//   - An error if the message couldn't be stored.
+func (s *System) enqueueBroadcast(peer string, flight models.Flight) (*models.OutboxMessage, error) {
+	queued, err := dao.GetOutboxDAO().FindByPeerAndFlight(peer, flight.UniqueId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range queued {
+		var queuedFlight models.Flight
+		if err := json.Unmarshal([]byte(entry.Flight), &queuedFlight); err == nil &&
+			queuedFlight.Version > flight.Version {
+			return nil, nil
+		}
+		dao.GetOutboxDAO().Delete(entry)
+	}
+
+	jsonFlight, err := json.Marshal(flight)
+	if err != nil {
+		return nil, err
+	}
+
+	return dao.GetOutboxDAO().Insert(models.OutboxMessage{
+		Peer:        peer,
+		FlightId:    flight.UniqueId,
+		Flight:      string(jsonFlight),
+		NextAttempt: time.Now(),
+	})
+}
+
+// enqueueMessage stores in the outbox of a connection a message to an endpoint other than the broadcast. The message
+// is created once, so every delivery attempt carries the same ID and the peer applies it only once.
+//
+// Return:
+//   - The stored outbox message.
+//   - An error if the message couldn't be created or stored.
+func (s *System) enqueueMessage(peer string, method string, path string, body interface{}) (*models.OutboxMessage, error) {
+	message, err := models.CreateMessage(s.ServerId.String(), peer, s.VectorClock, body)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonMessage, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return dao.GetOutboxDAO().Insert(models.OutboxMessage{
+		Peer:        peer,
+		Method:      method,
+		Path:        path,
+		Message:     string(jsonMessage),
+		NextAttempt: time.Now(),
+	})
+}
+
+// deliverOutbox tries to deliver an outbox message to its connection. Delivered messages are removed
+// from the outbox, as are the ones refused by the connection with a client error, which another attempt would
+// get again; the other failures are rescheduled with exponential backoff. The broadcasts are sent with the
+// given copy of the clock (see outboxClock).
+func (s *System) deliverOutbox(id string, conn models.Connection, entry models.OutboxMessage, clock map[string]int) bool {
+	var status int
+	if entry.Path != "" {
+		status = s.sendStoredMessage(conn, entry)
+	} else {
+		var flight models.Flight
+		if err := json.Unmarshal([]byte(entry.Flight), &flight); err != nil {
+			log.Printf("Dropping invalid outbox message %d: %v", entry.ID, err)
+			dao.GetOutboxDAO().Delete(entry)
+			return false
+		}
+
+		message, err := models.CreateMessage(s.ServerId.String(), id, clock, flight)
+		if err != nil {
+			log.Printf("Error creating message for flight %s: %v", flight.UniqueId, err)
+			return false
+		}
+		url := URL_PREFIX + conn.Address + ":" + conn.Port + "/server/broadcast"
+		status = s.sendFlight(url, flight, *message)
+	}
+
+	// Uma mensagem recusada pelo servidor não é aceita em outra tentativa
+	if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+		log.Printf("Dropping outbox message %d refused by %s with status %d", entry.ID, conn.Name, status)
+		dao.GetOutboxDAO().Delete(entry)
+		return false
+	}
+
+	if status == http.StatusOK {
+		dao.GetOutboxDAO().Delete(entry)
+		return true
+	}
+
+	entry.Attempts++
+	entry.NextAttempt = time.Now().Add(outboxBackoff(entry.Attempts))
+	dao.GetOutboxDAO().Update(entry)
+	return false
+}
+
+// outboxClock returns a copy of the system clock, read under the lock, for the deliveries made outside a broadcast.
+func (s *System) outboxClock() map[string]int {
+	s.Lock.RLock()
+	defer s.Lock.RUnlock()
+	return copyClock(s.VectorClock)
+}
+
+// sendStoredMessage sends a stored outbox message to the endpoint of its connection.
+//
+// Return:
+//   - The HTTP status code of the response, or 0 if the request couldn't be sent.
+func (s *System) sendStoredMessage(conn models.Connection, entry models.OutboxMessage) int {
+	req, err := http.NewRequest(entry.Method, URL_PREFIX+conn.Address+":"+conn.Port+entry.Path, strings.NewReader(entry.Message))
+	if err != nil {
+		log.Printf("Error creating request for outbox message %d: %v", entry.ID, err)
+		return 0
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: CONNECTION_TIMEOUT}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("Error sending outbox message %d to %s: %v", entry.ID, conn.Name, err)
+		return 0
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Outbox message %d to %s failed with status: %s", entry.ID, conn.Name, resp.Status)
+	}
+	return resp.StatusCode
+}
+
+// outboxBackoff returns the delay before the next delivery attempt of a message,
+// doubling it after every failed attempt up to OUTBOX_MAX_BACKOFF.
+func outboxBackoff(attempts int) time.Duration {
+	backoff := OUTBOX_BASE_BACKOFF
+	for i := 1; i < attempts && backoff < OUTBOX_MAX_BACKOFF; i++ {
+		backoff *= 2
+	}
+
+	if backoff > OUTBOX_MAX_BACKOFF {
+		return OUTBOX_MAX_BACKOFF
+	}
+	return backoff
+}
+
+// flushOutbox delivers, in order, every queued message of a connection regardless of its backoff.
+// It is used when a connection comes back online. Delivery stops at the first failure.
+func (s *System) flushOutbox(id string) {
+	s.Lock.RLock()
+	conn, exists := s.Connections[id]
+	s.Lock.RUnlock()
+
+	if !exists {
+		return
+	}
+
+	entries, err := dao.GetOutboxDAO().FindByPeer(id)
+	if err != nil {
+		return
+	}
+
+	clock := s.outboxClock()
+	for _, entry := range entries {
+		if !s.deliverOutbox(id, conn, entry, clock) {
+			return
+		}
+	}
+
+	if len(entries) > 0 {
+		log.Printf("Redelivered %d queued messages to %s", len(entries), conn.Name)
+	}
+}
+
+// retryOutbox periodically retries the queued messages whose backoff has expired.
+// Messages to offline connections wait until the heartbeat sees the connection online again,
+// and messages to removed connections are dropped.
+func (s *System) retryOutbox() {
+	ticker := time.NewTicker(OUTBOX_RETRY_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		entries, err := dao.GetOutboxDAO().FindDue(time.Now())
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			s.Lock.RLock()
+			conn, exists := s.Connections[entry.Peer]
+			s.Lock.RUnlock()
+
+			if !exists {
+				dao.GetOutboxDAO().Delete(entry)
+				continue
+			}
+
+			if conn.IsOnline {
+				s.deliverOutbox(entry.Peer, conn, entry, s.outboxClock())
+			}
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"passcom/internal/models"
+	"time"
+)
+
+// quote returns the sale price of a seat of a fare class of a flight of this company, computed by the configured
+// pricing engine from the seats still available. It must be called before the seat is taken, and only by the owner
+// of the flight, which sends the price to the other servers along with the seat.
+//
+// Return:
+//   - The price of the seat, or 0 if the flight doesn't have the fare class.
+func quote(flight models.Flight, class string) uint {
+	fare, exists := flight.Fare(class)
+	if !exists {
+		return 0
+	}
+	return pricing.Price(flight, fare, time.Now())
+}
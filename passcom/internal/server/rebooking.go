@@ -0,0 +1,414 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// handleRebooking is a HTTP handler function for the rebooking offers of the authenticated user.
+// GET lists the offers and refunds of the tickets of cancelled flights, and POST accepts or declines
+// the offer given by the id query parameter.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleRebooking(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	token := r.Header.Get("Authorization")
+
+	switch r.Method {
+	case http.MethodGet:
+		returnResponse(w, r, GetRebookings(models.Request{
+			Auth: token,
+		}))
+	case http.MethodPost:
+		id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 32)
+		if err != nil {
+			returnResponse(w, r, models.Response{
+				Error:  "not valid id",
+				Status: http.StatusBadRequest,
+			})
+			return
+		}
+
+		var decision models.RebookingDecision
+		if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		withClientIdempotency(w, r, decision, func(w http.ResponseWriter) {
+			returnResponse(w, r, DecideRebooking(uint(id), models.Request{
+				Auth: token,
+				Data: decision,
+			}))
+		})
+	default:
+		http.Error(w, "only GET or POST allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetRebookings returns the rebooking offers and refunds of the authenticated client.
+func GetRebookings(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	rebookings, err := dao.GetRebookingDAO().FindByClient(session.ClientID)
+	if err != nil {
+		return models.Response{
+			Error:  "failed to find rebookings",
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"rebookings": rebookings,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// DecideRebooking accepts or declines a rebooking offer of the authenticated client.
+// Accepting buys the offered itinerary atomically, as in BuyItinerary; declining refunds the cancelled ticket.
+//
+// Parameters:
+//   - id: The ID of the rebooking.
+//   - request: The request containing the authentication token and the RebookingDecision data.
+//
+// Return:
+//   - A response with the updated rebooking, or the reason why it couldn't be decided.
+func DecideRebooking(id uint, request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	var decision models.RebookingDecision
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &decision)
+
+	rebooking, err := dao.GetRebookingDAO().FindById(id)
+	if err != nil || rebooking.ClientId != session.ClientID {
+		return models.Response{
+			Error:  "rebooking not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	// A oferta de um ticket comprado em outro servidor é decidida pelo login federado nesse servidor
+	if rebooking.Server != "" {
+		return models.Response{
+			Error:  "rebooking decided on " + rebooking.Server + ", where the ticket was bought",
+			Status: http.StatusConflict,
+		}
+	}
+
+	if rebooking.Status != models.RebookingOffered {
+		return models.Response{
+			Error:  "rebooking already " + rebooking.Status,
+			Status: http.StatusConflict,
+		}
+	}
+
+	if decision.Accept {
+		flights := make([]models.Flight, len(rebooking.Itinerary))
+		for i, uniqueId := range rebooking.Itinerary {
+			flight, err := dao.GetFlightDAO().FindByUniqueId(uniqueId)
+			if err != nil {
+				return models.Response{
+					Error:  "offered flight no longer available",
+					Status: http.StatusConflict,
+				}
+			}
+			flights[i] = *flight
+		}
+
+		classes := make([]string, len(flights))
+		for i := range classes {
+			classes[i] = rebooking.Class
+		}
+
+		if err := instance.initiateItinerary(flights, nil, classes, session.ClientID); err != nil {
+			return models.Response{
+				Error:  err.Error(),
+				Status: http.StatusNotAcceptable,
+			}
+		}
+		rebooking.Status = models.RebookingAccepted
+	} else {
+		rebooking.Status = models.RebookingRefunded
+		rebooking.Refund = rebooking.Price
+	}
+
+	dao.GetRebookingDAO().Update(*rebooking)
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"rebooking": rebooking,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// rebookTickets handles the tickets of the local clients on a cancelled flight. Each ticket gets an offer of
+// the next itinerary from the origin to the destination of the flight in the fare class of the ticket or, if
+// there is none, a refund of the price paid, and is then removed. Every server runs it for its own clients when it applies the cancellation, so the clients of
+// other companies that bought a seat on the flight are handled by their own servers. The offers and refunds of the
+// tickets of federated clients are sent to their home companies (see notifyAffectedTickets).
+func rebookTickets(flight models.Flight) {
+	tickets, err := dao.GetTicketDAO().FindByFlightId(flight.ID)
+	if err != nil || len(tickets) == 0 {
+		return
+	}
+
+	itineraries := make(map[string][]models.Flight)
+	// Tickets dos clientes federados, por companhia de origem
+	homes := make(map[string]models.AffectedTickets)
+	flight.Tickets = nil
+
+	for _, ticket := range tickets {
+		itinerary, found := itineraries[ticket.Class]
+		if !found {
+			itinerary = nextItinerary(flight, ticket.Class)
+			itineraries[ticket.Class] = itinerary
+		}
+
+		// Tickets vendidos antes das classes tarifárias não registram o preço pago
+		price := ticket.Price
+		if price == 0 {
+			price = flight.Price
+		}
+
+		rebooking := models.Rebooking{
+			ClientId:             ticket.ClientId,
+			TicketId:             ticket.UniqueId,
+			FlightId:             flight.UniqueId,
+			Company:              flight.Company,
+			OriginAirportID:      flight.OriginAirportID,
+			DestinationAirportID: flight.DestinationAirportID,
+			Departure:            flight.Departure,
+			Class:                ticket.Class,
+			Price:                price,
+			Itinerary:            make([]string, 0, len(itinerary)),
+			Status:               models.RebookingOffered,
+		}
+		for _, leg := range itinerary {
+			rebooking.Itinerary = append(rebooking.Itinerary, leg.UniqueId)
+		}
+		if len(itinerary) == 0 {
+			rebooking.Status = models.RebookingRefunded
+			rebooking.Refund = price
+		}
+
+		// O ticket só é removido depois que a oferta ou o reembolso foi registrado
+		if err := dao.GetRebookingDAO().Insert(rebooking); err != nil {
+			continue
+		}
+		dao.GetTicketDAO().Delete(ticket)
+
+		if client, err := dao.GetClientDAO().FindById(ticket.ClientId); err == nil && client.HomeCompany != "" {
+			username, _, _ := strings.Cut(client.Username, "@")
+			affected, found := homes[client.HomeCompany]
+			if !found {
+				affected = models.AffectedTickets{Flight: flight, Rebookings: make(map[string][]models.Rebooking)}
+			}
+			affected.TicketIds = append(affected.TicketIds, ticket.UniqueId)
+			affected.Rebookings[username] = append(affected.Rebookings[username], rebooking)
+			homes[client.HomeCompany] = affected
+		}
+	}
+
+	log.Printf("Cancelled flight %s: %d tickets rebooked or refunded", flight.UniqueId, len(tickets))
+
+	if len(homes) > 0 {
+		// O aviso é enviado fora do lock do sistema, que pode estar com quem aplicou o cancelamento
+		go func() {
+			for home, affected := range homes {
+				if id, _ := instance.FindConnectionByName(home); id != "" {
+					instance.notifyAffectedTickets(id, affected)
+				} else {
+					log.Printf("Connection not found to notify %s of the tickets of flight %s", home, flight.UniqueId)
+				}
+			}
+		}()
+	}
+}
+
+// notifyCoordinators tells the servers that bought seats of a cancelled flight of this server for their clients,
+// through the two-phase commit, which of their tickets were affected. The cancelled flight goes with the tickets,
+// so they are rebooked even if the server missed the broadcast of the cancellation.
+func (s *System) notifyCoordinators(flight models.Flight) {
+	transactions, err := dao.GetTransactionDAO().FindByFlightId(flight.UniqueId)
+	if err != nil {
+		return
+	}
+
+	flight.Tickets = nil
+	coordinators := make(map[string]models.AffectedTickets)
+	for _, transaction := range transactions {
+		// O UniqueId do ticket criado pelo coordenador é o ID da transação
+		if transaction.Role != models.RoleParticipant || transaction.Type != models.TypePurchase ||
+			transaction.Status != models.COMMITED {
+			continue
+		}
+		affected := coordinators[transaction.Coordinator]
+		affected.Flight = flight
+		affected.TicketIds = append(affected.TicketIds, transaction.TransactionId)
+		coordinators[transaction.Coordinator] = affected
+	}
+
+	for id, affected := range coordinators {
+		s.notifyAffectedTickets(id, affected)
+	}
+}
+
+// notifyAffectedTickets queues in the outbox of a connection the tickets of its clients affected by the cancellation
+// of a flight, delivering them right away when the connection is online. Failed deliveries are retried with the
+// other outbox messages. The caller must not hold the system lock.
+func (s *System) notifyAffectedTickets(id string, affected models.AffectedTickets) {
+	s.Lock.RLock()
+	conn, exists := s.Connections[id]
+	s.Lock.RUnlock()
+	if !exists {
+		log.Printf("Connection %s not found to notify the tickets of flight %s", id, affected.Flight.UniqueId)
+		return
+	}
+
+	entry, err := s.enqueueMessage(id, http.MethodPost, "/server/tickets/affected", affected)
+	if err != nil {
+		log.Printf("Error storing affected tickets of flight %s to %s: %v", affected.Flight.UniqueId, conn.Name, err)
+		return
+	}
+
+	if conn.IsOnline {
+		s.deliverOutbox(id, conn, *entry, s.outboxClock())
+	}
+}
+
+// HandleAffectedTickets receives from another server the tickets of the clients of this server affected by the
+// cancellation of a flight. A cancellation sent by the owner of the flight is applied as in the anti-entropy,
+// rebooking or refunding the tickets stored here; the offers and refunds of the tickets that the clients of this
+// company bought on the sender, through the federated login, are recorded so the clients see them in /rebooking.
+// Retries of the same message are answered with the stored response.
+func (s *System) HandleAffectedTickets(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	var affected models.AffectedTickets
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil || decodeBody(msg.Body, &affected) != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.RLock()
+	conn, exists := s.Connections[msg.From]
+	s.Lock.RUnlock()
+	if !exists {
+		http.Error(w, "Unknown server", http.StatusForbidden)
+		return
+	}
+
+	s.withIdempotency(w, messageKey(msg), requestHash(msg.Body), func(w http.ResponseWriter) {
+		// Somente o dono do voo informa o seu cancelamento
+		if affected.Flight.Company == conn.Name && affected.Flight.DeletedAt.Valid {
+			s.Lock.Lock()
+			s.repairFlights([]models.Flight{affected.Flight}, conn.Name)
+			s.Lock.Unlock()
+		}
+
+		recorded := 0
+		for username, rebookings := range affected.Rebookings {
+			client, err := dao.GetClientDAO().FindByUsername(username)
+			if err != nil || client.HomeCompany != "" {
+				continue
+			}
+			for _, rebooking := range rebookings {
+				rebooking.Model = gorm.Model{}
+				rebooking.ClientId = client.ID
+				rebooking.Server = conn.Name
+				if dao.GetRebookingDAO().Insert(rebooking) == nil {
+					recorded++
+				}
+			}
+		}
+		log.Printf("Received %d affected tickets of flight %s from %s, %d offers or refunds recorded",
+			len(affected.TicketIds), affected.Flight.UniqueId, conn.Name, recorded)
+
+		responseMsg, err := models.CreateMessage(s.ServerId.String(), msg.From, s.VectorClock, "")
+		if err != nil {
+			http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+			return
+		}
+
+		utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+	})
+}
+
+// nextItinerary finds the itinerary that replaces a cancelled flight: the one that arrives first at its
+// destination among those departing after it, or the one with the fewest legs if the flight has no schedule.
+// Only flights with available seats in the fare class are used; an empty class uses the first class of each flight.
+//
+// Return:
+//   - The flights of the itinerary, or nil if there is none.
+func nextItinerary(cancelled models.Flight, class string) []models.Flight {
+	mode := models.RouteFewestHops
+	if cancelled.Scheduled() {
+		mode = models.RouteFastest
+	}
+
+	search, _ := pathSearch(models.RouteRequest{Mode: mode, K: 1, Class: class})
+	now := time.Now()
+
+	allow := search.Allow
+	search.Allow = func(flight models.Flight) bool {
+		if flight.UniqueId == cancelled.UniqueId || !allow(flight) {
+			return false
+		}
+		if flight.Scheduled() && flight.Departure.Before(now) {
+			return false
+		}
+		return !cancelled.Scheduled() || !flight.Departure.Before(cancelled.Departure)
+	}
+
+	if cancelled.Scheduled() {
+		// O primeiro trecho conta a partir da partida original, para que o itinerário com a chegada mais cedo vença
+		weight := search.Weight
+		search.Weight = func(previous *models.Flight, flight models.Flight) uint {
+			if previous == nil {
+				return uint(flight.Arrival.Sub(cancelled.Departure).Minutes())
+			}
+			return weight(previous, flight)
+		}
+	}
+
+	paths, err := dao.GetFlightDAO().FindShortestPaths(cancelled.OriginAirportID, cancelled.DestinationAirportID, search)
+	if err != nil {
+		return nil
+	}
+	return paths[0]
+}
@@ -0,0 +1,362 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"slices"
+	"time"
+)
+
+func GetAirports(request models.Request) models.Response {
+	_, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error: "not authorized",
+		}
+	}
+	responseData := make([]map[string]interface{}, 0)
+
+	airports := dao.GetAirportDAO().FindAll()
+
+	for _, airport := range airports {
+
+		airportresponse := make(map[string]interface{})
+		airportresponse["Name"] = airport.Name
+		airportresponse["City"] = airport.City
+		responseData = append(responseData, airportresponse)
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"Airports": responseData,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// AllRoutes handles the retrieval of all available routes.
+// It checks if the provided authentication token is valid and returns a list of all routes if authorized.
+//
+// Parameters:
+//   - auth: A string representing the authentication token provided by the client.
+//   - conn: A net.Conn object representing the connection to the client.
+func AllRoutes(auth string, conn net.Conn) models.Response {
+
+	_, exists := SessionIfExists(auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	dao := dao.GetFlightDAO()
+	dao.New()
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"all-routes": dao.FindAll(),
+		},
+		Status: http.StatusOK,
+	}
+
+}
+
+// Route handles the retrieval of a route between two cities.
+// It checks if the provided authentication token is valid and returns a route if authorized.
+// If the source or destination city is not found, it returns an error response.
+// If no route is found between the source and destination cities, it returns an error response.
+// When a search mode, a number of itineraries or a filter is given, the routes are ranked itineraries (see rankedRoutes).
+// Otherwise, the direct flights are followed by the path with the fewest legs whose flights connect.
+
+// Parameters:
+//   - auth: A string representing the authentication token provided by the client.
+//   - data: An interface containing the source and destination city names.
+//   - conn: A net.Conn object representing the connection to the client.
+
+func Route(request models.Request) models.Response {
+	_, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	var routeRequest models.RouteRequest
+	var response models.Response
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &routeRequest)
+
+	src := dao.GetAirportDAO().FindByName(routeRequest.Source)
+	dest := dao.GetAirportDAO().FindByName(routeRequest.Dest)
+
+	if src == nil || dest == nil {
+		return models.Response{
+			Error:  "not valid city name",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if routeRequest.Ranked() {
+		return rankedRoutes(src.ID, dest.ID, routeRequest)
+	}
+
+	search, errResponse := pathSearch(models.RouteRequest{Mode: models.RouteFewestHops, Date: routeRequest.Date})
+	if errResponse != nil {
+		return *errResponse
+	}
+
+	paths, paths_err := dao.GetFlightDAO().FindBySourceAndDest(src.ID, dest.ID)
+	paths = utils.Filter(paths, func(flight models.Flight) bool { return search.CanFollow(nil, flight) })
+	shortestpaths, cherr := dao.GetFlightDAO().FindShortestPaths(src.ID, dest.ID, search)
+	if cherr == nil {
+		paths = append(paths, shortestpaths[0]...)
+	}
+	if paths == nil {
+		paths = make([]models.Flight, 0)
+	}
+
+	if paths_err != nil && cherr != nil {
+		response.Error = "no route"
+	} else {
+
+		response.Data = map[string]interface{}{
+			"paths": paths,
+		}
+		response.Status = http.StatusOK
+	}
+
+	return response
+}
+
+// rankedRoutes finds up to K loop-free itineraries between two airports over the flights of all companies,
+// ranked by the search mode.
+//
+// Parameters:
+//   - src: The ID of the origin airport.
+//   - dest: The ID of the destination airport.
+//   - routeRequest: The search mode, the number of itineraries, the maximum number of legs, the company filters,
+//     the departure date and the fare class (see pathSearch).
+//
+// Return:
+//   - A response with the itineraries, each with its flights, total price in the fare class and number of legs.
+//     The best itinerary is also returned at the top level, as in a single route search.
+//   - An error response if the request is not valid or if there is no route.
+func rankedRoutes(src uint, dest uint, routeRequest models.RouteRequest) models.Response {
+	if routeRequest.K > ROUTE_MAX_ITINERARIES {
+		return models.Response{
+			Error:  fmt.Sprintf("at most %d itineraries can be requested", ROUTE_MAX_ITINERARIES),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	search, errResponse := pathSearch(routeRequest)
+	if errResponse != nil {
+		return *errResponse
+	}
+
+	paths, err := dao.GetFlightDAO().FindShortestPaths(src, dest, search)
+	if err != nil {
+		return models.Response{
+			Error:  "no route",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	itineraries := make([]map[string]interface{}, len(paths))
+	for i, path := range paths {
+		var price uint
+		for _, flight := range path {
+			fare, _ := flight.Fare(routeRequest.Class)
+			price += fare.Price
+		}
+
+		itinerary := map[string]interface{}{
+			"paths": path,
+			"price": price,
+			"legs":  len(path),
+		}
+		if path[0].Scheduled() && path[len(path)-1].Scheduled() {
+			itinerary["duration"] = path[len(path)-1].Arrival.Sub(path[0].Departure).String()
+		}
+		itineraries[i] = itinerary
+	}
+
+	data := make(map[string]interface{})
+	for key, value := range itineraries[0] {
+		data[key] = value
+	}
+	data["itineraries"] = itineraries
+
+	return models.Response{
+		Data:   data,
+		Status: http.StatusOK,
+	}
+}
+
+// pathSearch builds the rules of an itinerary search from a route request.
+//
+// The weight of each flight depends on the mode: models.RouteCheapest minimises the total price,
+// models.RouteFewestHops (the default) the number of legs and models.RouteFastest the time from the
+// first departure to the last arrival, counting the connections. Only flights with available seats in the
+// fare class of the request, or in their first class if none is given, are used, and their prices are the
+// prices of that class. Only scheduled flights are used in
+// the fastest mode or when a departure date is given, and then the first flight must depart on that date.
+// A scheduled flight only follows another one if it departs at least the configured minimum connection
+// time after the previous arrival.
+//
+// Return:
+//   - The rules of the search.
+//   - An error response if the mode or the date are not valid.
+func pathSearch(routeRequest models.RouteRequest) (models.PathSearch, *models.Response) {
+	search := models.PathSearch{
+		K:       routeRequest.K,
+		MaxLegs: routeRequest.MaxLegs,
+	}
+	scheduledOnly := routeRequest.Date != ""
+
+	switch routeRequest.Mode {
+	case models.RouteCheapest:
+		search.Weight = func(previous *models.Flight, flight models.Flight) uint {
+			fare, _ := flight.Fare(routeRequest.Class)
+			return fare.Price
+		}
+	case models.RouteFewestHops, "":
+		search.Weight = func(previous *models.Flight, flight models.Flight) uint { return 1 }
+	case models.RouteFastest:
+		// Duração do voo somada à espera na conexão, em minutos
+		search.Weight = func(previous *models.Flight, flight models.Flight) uint {
+			if previous == nil {
+				return uint(flight.Duration().Minutes())
+			}
+			return uint(flight.Arrival.Sub(previous.Arrival).Minutes())
+		}
+		scheduledOnly = true
+	default:
+		return search, &models.Response{
+			Error:  "not valid mode",
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if routeRequest.Date != "" {
+		if _, err := time.Parse(time.DateOnly, routeRequest.Date); err != nil {
+			return search, &models.Response{
+				Error:  "not valid date",
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	search.Allow = func(flight models.Flight) bool {
+		if scheduledOnly && !flight.Scheduled() {
+			return false
+		}
+		if fare, exists := flight.Fare(routeRequest.Class); !exists || fare.Seats <= 0 {
+			return false
+		}
+		if len(routeRequest.Companies) > 0 && !slices.Contains(routeRequest.Companies, flight.Company) {
+			return false
+		}
+		return !slices.Contains(routeRequest.Exclude, flight.Company)
+	}
+
+	minConnection := cfg.MinConnectionTime()
+	search.CanFollow = func(previous *models.Flight, flight models.Flight) bool {
+		if previous == nil {
+			// A data é a do fuso horário do aeroporto de origem
+			return routeRequest.Date == "" || flight.Departure.Format(time.DateOnly) == routeRequest.Date
+		}
+		if !previous.Scheduled() || !flight.Scheduled() {
+			return true // Voos sem horário não possuem regra de conexão
+		}
+		return !flight.Departure.Before(previous.Arrival.Add(minConnection))
+	}
+
+	return search, nil
+}
+
+// Flights handles the retrieval of flight details based on provided flight IDs.
+// It checks if the provided authentication token is valid and returns flight details if authorized.
+// If any of the provided flight IDs does not exist, it returns an error response.
+//
+// Parameters:
+//   - auth: A string representing the authentication token provided by the client.
+//   - data: An interface containing the flight IDs.
+//   - conn: A net.Conn object representing the connection to the client.
+//
+// Return:
+//   - This function does not return any value. It writes a response to the client's connection.
+//   - The response contains flight details if authorized and valid flight IDs are provided.
+//   - If not authorized, it returns an error response with the message "not authorized".
+//   - If any of the provided flight IDs does not exist, it returns an error response.
+func Flights(request models.Request) models.Response {
+	_, exists := SessionIfExists(request.Auth)
+	if !exists {
+		return models.Response{
+			Error: "not authorized",
+		}
+	}
+
+	var flightsRequest models.FlightsRequest
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &flightsRequest)
+
+	responseData, err := getRoute(flightsRequest.FlightIds)
+	if err != nil {
+		return models.Response{
+			Error:  err.Error(),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"Flights": responseData,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// getRoute retrieves flight details for a given list of flight IDs.
+// It fetches the flight details from the database and constructs a response containing the flight details.
+//
+// Parameters:
+//   - flightIds: A slice of uuid.UUID representing the flight IDs for which the details need to be retrieved.
+//
+// Return:
+//   - A slice of map[string]interface{} containing the flight details. Each map represents a flight and contains the following keys:
+//   - "Seats": An integer representing the number of available seats on the flight.
+//   - "Fares": The fare classes of the flight, with the price and available seats of each one.
+//   - "Src": A string representing the source city of the flight.
+//   - "Dest": A string representing the destination city of the flight.
+//   - An error if any of the provided flight IDs does not exist in the database.
+func getRoute(flightIds []uint) ([]map[string]interface{}, error) {
+	responseData := make([]map[string]interface{}, len(flightIds))
+	for i, id := range flightIds {
+		flightresponse := make(map[string]interface{})
+		flight, err := dao.GetFlightDAO().FindById(id)
+		if err != nil {
+			return nil, fmt.Errorf("some flight doesn't exist: %v", id)
+		}
+		fmt.Println(flight.OriginAirport)
+
+		flightresponse["Seats"] = flight.Seats
+		flightresponse["Fares"] = flight.FareClasses()
+		flightresponse["Src"] = flight.OriginAirport.City.Name
+		flightresponse["Dest"] = flight.DestinationAirport.City.Name
+		responseData[i] = flightresponse
+	}
+	return responseData, nil
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"log"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"time"
+)
+
+// GenerateScheduledFlights materialises the flights of a company's schedules that depart within the next days,
+// storing the ones that were never generated. A generated flight that was later deleted is not created again.
+//
+// Parameters:
+//   - company: The company whose schedules are generated.
+//   - from: The start of the horizon.
+//   - days: The length of the horizon, in days.
+//
+// Return:
+//   - The flights created.
+//   - An error if the schedules couldn't be read.
+func GenerateScheduledFlights(company string, from time.Time, days int) ([]models.Flight, error) {
+	schedules, err := dao.GetScheduleDAO().FindByCompany(company)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]models.Flight, 0)
+	until := from.AddDate(0, 0, days)
+	for _, schedule := range schedules {
+		if err := schedule.Validate(); err != nil {
+			log.Printf("Skipping invalid schedule: %v", err)
+			continue
+		}
+
+		for _, flight := range schedule.FlightsBetween(from, until) {
+			if dao.GetFlightDAO().ExistsByUniqueId(flight.UniqueId) {
+				continue
+			}
+			dao.GetFlightDAO().Insert(flight)
+			created = append(created, flight)
+		}
+	}
+
+	log.Printf("%d flights generated from the schedules of %s", len(created), company)
+	return created, nil
+}
+
+// generateSchedules generates the flights of the server's schedules for the next days and,
+// if any flight was created, sends the company's flights to the online connections through the database sync.
+// Offline connections receive them when they reconnect or in the next anti-entropy round.
+//
+// Return:
+//   - The number of flights created.
+func (s *System) generateSchedules(days int) int {
+	created, err := GenerateScheduledFlights(s.ServerName, time.Now(), days)
+	if err != nil {
+		log.Printf("Error generating scheduled flights: %v", err)
+		return 0
+	}
+	if len(created) == 0 {
+		return 0
+	}
+
+	s.Lock.RLock()
+	connections := make(map[string]models.Connection, len(s.Connections))
+	for id, conn := range s.Connections {
+		connections[id] = conn
+	}
+	s.Lock.RUnlock()
+
+	for id, conn := range connections {
+		if conn.IsOnline {
+			s.SendDatabase(id, conn.Address, conn.Port)
+		}
+	}
+
+	return len(created)
+}
+
+// periodicScheduleGeneration keeps the flights of the schedules generated for the configured number of days,
+// as the horizon moves forward.
+func (s *System) periodicScheduleGeneration() {
+	s.generateSchedules(cfg.ScheduleDays)
+
+	ticker := time.NewTicker(SCHEDULE_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.generateSchedules(cfg.ScheduleDays)
+	}
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"strconv"
+)
+
+// handleGetSeats is an HTTP handler function that returns the seat map of the flight given by the id query parameter.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleGetSeats(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 32)
+	if err != nil {
+		returnResponse(w, r, models.Response{
+			Error:  "not valid id",
+			Status: http.StatusBadRequest,
+		})
+		return
+	}
+
+	returnResponse(w, r, SeatMap(uint(id), models.Request{
+		Auth: r.Header.Get("Authorization"),
+	}))
+}
+
+// SeatMap returns the layout of a flight and its seats, with the ones already assigned marked as taken.
+// The map of a flight of another company is the one last received from its owner.
+//
+// Parameters:
+//   - id: The ID of the flight.
+//   - request: The request containing the authentication token.
+//
+// Return:
+//   - A response with the layout, the seats, the number of available seats and the fare classes of the flight.
+func SeatMap(id uint, request models.Request) models.Response {
+	_, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	flight, err := dao.GetFlightDAO().FindById(id)
+	if err != nil {
+		return models.Response{
+			Error:  "flight not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"Layout":    flight.SeatLayout(),
+			"Seats":     flight.SeatMap(),
+			"Available": flight.Seats,
+			"Fares":     flight.FareClasses(),
+		},
+		Status: http.StatusOK,
+	}
+}
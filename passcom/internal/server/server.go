@@ -1,14 +1,15 @@
 package server
 
 import (
-	"boreal/internal/models"
-	"boreal/internal/utils"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"passcom/internal/config"
+	"passcom/internal/models"
+	"passcom/internal/utils"
 	"sync"
 	"syscall"
 	"time"
@@ -28,20 +29,45 @@ type System struct {
 	Lock        sync.RWMutex
 	wg          sync.WaitGroup // WaitGroup para controlar goroutines
 	shutdown    chan os.Signal // Canal para sinalizar o encerramento
+	pending     sync.Map       // Itinerários sendo coordenados por este processo
+	EscrowQuota int            // Assentos de cada voo próprio concedidos em custódia a cada conexão (0 desativa)
+	inflight    sync.Map       // Requisições idempotentes em execução
+	changes     int            // Maior valor do próprio relógio gravado em um voo próprio
+	changesOnce sync.Once
 }
 
 const (
-	SERVER_NAME        = "boreal"
-	ADDRESS            = "localhost"
-	PORT               = "9999"
-	CLIPORT            = ":7772"
-	INSTANCE_PATH      = "systemvars.json"
 	BUFFER_SIZE        = 100
 	LOG_SIZE           = 1000
 	CONNECTION_TIMEOUT = 10 * time.Second
 	HEARTBEAT_TIMER    = 1 * time.Second
-	SESSION_TIME_LIMIT = 30 * time.Minute
 	URL_PREFIX         = "http://"
+
+	TRANSACTION_RETRY_TIMER = 5 * time.Second
+
+	OUTBOX_RETRY_TIMER  = 1 * time.Second
+	OUTBOX_BASE_BACKOFF = 1 * time.Second
+	OUTBOX_MAX_BACKOFF  = 1 * time.Minute
+
+	ANTI_ENTROPY_TIMER   = 30 * time.Second
+	ANTI_ENTROPY_BUCKETS = 16
+
+	ESCROW_TIMER = 5 * time.Second
+
+	HOLD_DEFAULT_TTL = 10 * time.Minute
+	HOLD_MAX_TTL     = 30 * time.Minute
+	HOLD_SWEEP_TIMER = 10 * time.Second
+
+	IDEMPOTENCY_WINDOW      = 24 * time.Hour
+	IDEMPOTENCY_SWEEP_TIMER = 10 * time.Minute
+
+	SERVER_REQUEST_RETRIES = 3
+
+	PEER_RETRY_TIMER = 5 * time.Second
+
+	ROUTE_MAX_ITINERARIES = 10
+
+	SCHEDULE_TIMER = 1 * time.Hour
 )
 
 const (
@@ -52,10 +78,22 @@ const (
 )
 
 var (
-	instance *System
-	once     sync.Once
+	instance  *System
+	once      sync.Once
+	cfg       = config.Default()
+	pricing   = models.NewPricingEngine(cfg.Pricing)
+	signer, _ = utils.NewTokenSigner(cfg.TokenAlg, cfg.TokenKey)
 )
 
+// Configure sets the company identity, ports, paths, pricing engine and token signer used by the server.
+// It must be called before GetInstance, since the instance is created from the configuration.
+func Configure(c config.Config) {
+	cfg = c
+	pricing = models.NewPricingEngine(cfg.Pricing)
+	signer, _ = utils.NewTokenSigner(cfg.TokenAlg, cfg.TokenKey)
+	utils.SetDbPath(cfg.DBPath)
+}
+
 // storeSystemVars saves the system variables to a JSON file named "systemvars.json".
 // It creates the file if it doesn't exist, overwriting any existing content.
 // The function serializes the system variables into a JSON object and writes it to the file.
@@ -75,6 +113,7 @@ func (s *System) storeSystemVars(path string) {
 	systemVars["Port"] = s.Port
 	systemVars["VectorClock"] = s.VectorClock
 	systemVars["Connections"] = s.Connections
+	systemVars["EscrowQuota"] = s.EscrowQuota
 
 	jsonData, err := json.MarshalIndent(systemVars, "", "  ") // identação
 	if err != nil {
@@ -110,8 +149,9 @@ func LoadInstanceFromFile(path string) (*System, error) {
 	}
 
 	// Restaurar canais e mapas, se necessário
+	loadedInstance.ServerName = cfg.Company
 	loadedInstance.Address = getLocalIP()
-	loadedInstance.Port = getPort()
+	loadedInstance.Port = cfg.Port
 	loadedInstance.Buffer = make(chan models.LogMessage, BUFFER_SIZE)
 	loadedInstance.shutdown = make(chan os.Signal, 1)
 
@@ -125,17 +165,17 @@ func LoadInstanceFromFile(path string) (*System, error) {
 // The function returns a pointer to the System instance.
 func GetInstance() *System {
 	once.Do(func() {
-		loadedInstance, err := LoadInstanceFromFile(INSTANCE_PATH)
+		loadedInstance, err := LoadInstanceFromFile(cfg.InstancePath)
 		if err == nil {
-			log.Printf("Server instance loaded from file %v", INSTANCE_PATH)
+			log.Printf("Server instance loaded from file %v", cfg.InstancePath)
 			instance = loadedInstance
 		} else {
-			log.Printf("Failed to load server instance from file %v due to: %v. Creating new instance...", INSTANCE_PATH, err)
+			log.Printf("Failed to load server instance from file %v due to: %v. Creating new instance...", cfg.InstancePath, err)
 			instance = &System{
-				ServerName:  SERVER_NAME,
+				ServerName:  cfg.Company,
 				ServerId:    uuid.New(),
 				Address:     getLocalIP(),
-				Port:        getPort(),
+				Port:        cfg.Port,
 				Log:         make([]models.LogMessage, LOG_SIZE),
 				Buffer:      make(chan models.LogMessage, BUFFER_SIZE),
 				VectorClock: make(map[string]int),
@@ -155,24 +195,44 @@ func GetInstance() *System {
 // The function starts a cleanup goroutine to remove expired sessions.
 // It registers HTTP handlers for client requests and server messages.
 // It sets up an HTTP server with the specified address and timeouts.
-// It starts goroutines to send heartbeats, handle CLI connections, and listen for system signals.
+// It starts goroutines to clean up sessions, expired holds and stored responses, send heartbeats, recover pending transactions, retry queued broadcasts,
+// reconcile replicas, rebalance escrow quotas, generate scheduled flights, handle CLI connections, connect to the configured peers, and listen for system signals.
 //
 // The function returns an error if the server fails to start or if an error occurs during shutdown.
 func (s *System) StartServer() error {
 	signal.Notify(s.shutdown, syscall.SIGINT, syscall.SIGTERM)
 
-	go s.CleanupSessions(SESSION_TIME_LIMIT)
-
-	// Usam requests dos clientes
-	http.HandleFunc("/login", handleLogin)
-	http.HandleFunc("/logout", handleLogout)
-	http.HandleFunc("/user", handleGetUser)
-	http.HandleFunc("/route", handleGetRoute)
-	http.HandleFunc("/flights", handleGetFlights)
-	http.HandleFunc("/ticket", handleTicket)
-	http.HandleFunc("/tickets", handleGetTickets)
-	http.HandleFunc("/airports", handleGetAirports)
-	http.HandleFunc("/wishlist", handleWishlist)
+	go s.CleanupSessions()
+
+	go s.CleanupHolds()
+
+	go s.CleanupIdempotencyRecords()
+
+	// Usam requests dos clientes, com as permissões de cada papel
+	http.HandleFunc("/login", authorize(handleLogin, public))
+	http.HandleFunc("/logout", authorize(handleLogout, everyone))
+	http.HandleFunc("/refresh", authorize(handleRefresh, public))
+	http.HandleFunc("/sessions", authorize(handleSessions, everyone))
+	http.HandleFunc("/register", authorize(handleRegister, public))
+	http.HandleFunc("/user", authorize(handleUser, everyone))
+	http.HandleFunc("/user/password", authorize(handleChangePassword, everyone))
+	http.HandleFunc("/route", authorize(handleGetRoute, everyone))
+	http.HandleFunc("/flights", authorize(handleGetFlights, everyone))
+	http.HandleFunc("/seats", authorize(handleGetSeats, everyone))
+	http.HandleFunc("/ticket", authorize(handleTicket, bookings))
+	http.HandleFunc("/tickets", authorize(handleGetTickets, bookings))
+	http.HandleFunc("/itinerary", authorize(handleItinerary, bookings))
+	http.HandleFunc("/airports", authorize(handleGetAirports, everyone))
+	http.HandleFunc("/wishlist", authorize(handleWishlist, shoppers))
+	http.HandleFunc("/hold", authorize(handleHold, bookings))
+	http.HandleFunc("/hold/checkout", authorize(handleCheckout, bookings))
+	http.HandleFunc("/rebooking", authorize(handleRebooking, bookings))
+
+	// API administrativa da companhia
+	http.HandleFunc("/admin/flights", authorize(s.handleAdminFlights, admins))
+	http.HandleFunc("/admin/flights/status", authorize(s.handleAdminFlightStatus, operators))
+	http.HandleFunc("/admin/roles", authorize(handleAdminRoles, admins))
+	http.HandleFunc("/admin/audit", authorize(handleAdminAudit, operators))
 
 	// Usam messages dos servidores
 	http.HandleFunc("/server/heartbeat", s.handleHeartbeat)
@@ -180,7 +240,21 @@ func (s *System) StartServer() error {
 	http.HandleFunc("/server/database", s.handleDatabase)
 	http.HandleFunc("/server/ticket/purchase", s.HandleServerTicketPurchase)
 	http.HandleFunc("/server/ticket/cancel", s.HandleServerTicketCancel)
+	http.HandleFunc("/server/ticket/prepare", s.HandlePrepare)
+	http.HandleFunc("/server/ticket/commit", s.HandleCommit)
+	http.HandleFunc("/server/ticket/abort", s.HandleAbort)
+	http.HandleFunc("/server/ticket/status", s.HandleTransactionStatus)
 	http.HandleFunc("/server/broadcast", s.HandleBroadcast)
+	http.HandleFunc("/server/changes", s.HandleChanges)
+	http.HandleFunc("/server/antientropy/digest", s.HandleDigest)
+	http.HandleFunc("/server/antientropy/repair", s.HandleRepair)
+	http.HandleFunc("/server/quota/sync", s.HandleQuotaSync)
+	http.HandleFunc("/server/hold", s.HandleServerHold)
+	http.HandleFunc("/server/hold/checkout", s.HandleHoldCheckout)
+	http.HandleFunc("/server/hold/release", s.HandleHoldRelease)
+	http.HandleFunc("/server/federation/credentials", s.HandleVerifyCredentials)
+	http.HandleFunc("/server/federation/tickets", s.HandleFederatedTickets)
+	http.HandleFunc("/server/tickets/affected", s.HandleAffectedTickets)
 
 	httpServer := &http.Server{
 		Addr:         s.Address + ":" + s.Port,
@@ -199,8 +273,20 @@ func (s *System) StartServer() error {
 
 	go s.sendHeartbeats()
 
+	go s.retryTransactions()
+
+	go s.retryOutbox()
+
+	go s.periodicAntiEntropy()
+
+	go s.periodicRebalance()
+
+	go s.periodicScheduleGeneration()
+
 	go s.HandleCLIServer()
 
+	go s.connectPeers()
+
 	select {
 	case <-s.shutdown:
 		err := s.shutdownServer(httpServer)
@@ -233,7 +319,7 @@ func (s *System) shutdownServer(server *http.Server) error {
 	defer s.Lock.Unlock()
 
 	// Save the system variables to a file
-	s.storeSystemVars(INSTANCE_PATH)
+	s.storeSystemVars(cfg.InstancePath)
 
 	// Close the HTTP server
 	return server.Close()
@@ -253,7 +339,7 @@ func (s *System) getServerInfo() string {
 	s.Lock.RLock()
 	defer s.Lock.RUnlock()
 	return fmt.Sprintf("\nName: %s\nAddress: %s\nPort: %s\nServerId: %s\n"+
-		"Connections: %v\nVector Clock:%v\n", s.ServerName, s.Address, s.Port,
+		"Connections: %v\nVector Clock:%v\n%s", s.ServerName, s.Address, s.Port,
 		s.ServerId, utils.PrintMap(s.Connections),
-		utils.PrintMap(s.VectorClock))
+		utils.PrintMap(s.VectorClock), s.quotaInfo())
 }
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CleanupSessions periodically removes the expired refresh tokens and the state kept in memory for the devices
+// that are no longer logged in. It runs every minute.
+func (s *System) CleanupSessions() {
+	select {
+	case <-s.shutdown:
+		log.Print("CleanupSessions stopped")
+		return
+	default:
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			dao.GetRefreshTokenDAO().DeleteExpired(time.Now())
+			for _, session := range dao.GetSessionDAO().FindAll() {
+				if _, err := dao.GetRefreshTokenDAO().FindByTokenId(session.ID.String()); err != nil {
+					fmt.Printf("Encerrando sessão %s\n", session.ID)
+					dao.GetSessionDAO().Delete(session)
+				}
+			}
+		}
+	}
+}
+
+// SessionIfExists checks if the given access token is valid: signed by this server and not expired.
+// The token is validated by its signature alone, so the returned session only identifies the client and
+// its device; the state kept in memory for the device is given by deviceState.
+//
+// Parameters:
+//   - token: A string representing the access token to be checked, optionally prefixed by "Bearer ".
+//
+// Return:
+//   - *models.Session: A pointer to the session of the token, or nil if the token is not valid.
+//   - bool: A boolean value indicating whether the token is valid (true) or not (false).
+func SessionIfExists(token string) (*models.Session, bool) {
+	var claims models.AccessClaims
+	if err := signer.Verify(strings.TrimPrefix(token, "Bearer "), &claims); err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	if claims.Expired(now) || claims.Issuer != cfg.Company {
+		return nil, false
+	}
+
+	id, err := uuid.Parse(claims.Session)
+	if err != nil {
+		return nil, false
+	}
+
+	// Tokens emitidos antes dos papéis não trazem o papel, e são de clientes
+	role := claims.Role
+	if role == "" {
+		role = models.RoleCustomer
+	}
+
+	return &models.Session{
+		ID:             id,
+		ClientID:       claims.Subject,
+		HomeCompany:    claims.Home,
+		Role:           role,
+		ActorID:        claims.Actor,
+		LastTimeActive: now,
+	}, true
+}
+
+// deviceState returns the state kept in memory for the device of a session, like its wishlist,
+// creating it on the first use.
+func deviceState(session *models.Session) *models.Session {
+	if state, err := dao.GetSessionDAO().FindById(session.ID); err == nil {
+		state.LastTimeActive = session.LastTimeActive
+		return state
+	}
+
+	state := &models.Session{ID: session.ID, ClientID: session.ClientID, HomeCompany: session.HomeCompany, LastTimeActive: session.LastTimeActive}
+	dao.GetSessionDAO().Insert(state)
+	return state
+}
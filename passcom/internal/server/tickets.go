@@ -0,0 +1,422 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"strconv"
+)
+
+// handleGetTickets handles HTTP GET requests to retrieve a list of tickets for the authenticated user.
+// It checks the request method to ensure it's a GET request and retrieves the user's authorization token from the request headers.
+// It then constructs a Request object with the appropriate action and authorization token, and sends it to the
+// The server's response is then decoded and returned as a JSON object in the HTTP response.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleGetTickets(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get("Authorization")
+	response := GetTickets(
+		models.Request{
+			Auth: token,
+		},
+	)
+
+	returnResponse(w, r, response)
+}
+
+// handleTicket is a HTTP handler function that handles requests for buying and canceling tickets.
+// It checks the HTTP method of the request and calls the appropriate handler function based on the method.
+// If the method is neither POST nor DELETE, it returns a 405 Method Not Allowed status with an error message.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleTicket(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	switch r.Method {
+	case http.MethodPost:
+		handleBuyTicket(w, r)
+	case http.MethodDelete:
+		handleCancelTicket(w, r)
+	default:
+		http.Error(w, "only POST or DELETE allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// handleBuyTicket is a HTTP handler function that handles requests for buying tickets.
+// It extracts the user's authorization token from the request headers and decodes the request body into a BuyTicket struct.
+// If the decoding fails, it returns a 400 Bad Request status.
+// It then constructs a Request object with the appropriate action, authorization token, and buy ticket data,
+// and sends it to the server using the writeAndReturnResponse function.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleBuyTicket(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("Authorization")
+	var buyTicket models.BuyTicket
+
+	err := json.NewDecoder(r.Body).Decode(&buyTicket)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	withClientIdempotency(w, r, buyTicket, func(w http.ResponseWriter) {
+		response := BuyTicket(models.Request{
+			Auth: token,
+			Data: buyTicket,
+		})
+
+		returnResponse(w, r, response)
+	})
+
+}
+
+// handleCancelTicket is a HTTP handler function that handles requests for canceling tickets.
+// It extracts the user's authorization token from the request headers and decodes the request body into a CancelBuyRequest struct.
+// If the decoding fails, it returns a 400 Bad Request status.
+// It then constructs a Request object with the appropriate action, authorization token, and cancel ticket data,
+// and sends it to the server using the writeAndReturnResponse function.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleCancelTicket(w http.ResponseWriter, r *http.Request) {
+
+	token := r.Header.Get("Authorization")
+
+	queryParams := r.URL.Query()
+
+	id := queryParams.Get("id")
+
+	idUint, err := strconv.ParseUint(id, 10, 32)
+
+	if err != nil {
+		returnResponse(w, r, models.Response{
+			Error:  err.Error(),
+			Status: http.StatusBadRequest,
+		})
+		return
+	}
+
+	withClientIdempotency(w, r, nil, func(w http.ResponseWriter) {
+		response := CancelBuy(uint(idUint), models.Request{
+			Auth: token,
+		})
+		returnResponse(w, r, response)
+	})
+}
+
+// GetTickets retrieves all tickets associated with the authenticated client.
+// It sends a response containing a list of tickets with their respective source, destination, and ID.
+//
+// Parameters:
+//   - auth: A string representing the authentication token.
+//   - conn: A net.Conn object representing the connection to the client.
+//
+// Return:
+//   - No return value.
+func GetTickets(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+	}
+	responseData := make([]map[string]interface{}, 0)
+
+	client, _ := dao.GetClientDAO().FindById(session.ClientID)
+
+	for _, ticket := range client.ClientFlights {
+		responseData = append(responseData, ticketView(ticket))
+	}
+
+	// Os tickets comprados em outros servidores pelo login federado ficam nesses servidores
+	if cfg.Federation && client.HomeCompany == "" {
+		responseData = append(responseData, instance.federatedTickets(client.Username)...)
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"Tickets": responseData,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// ticketView returns the fields of a ticket and of its flight shown to the client.
+func ticketView(ticket models.Ticket) map[string]interface{} {
+	flight := ticket.Flight
+
+	flightresponse := make(map[string]interface{})
+	flightresponse["Src"] = flight.OriginAirport.City
+	flightresponse["Dest"] = flight.DestinationAirport.City
+	flightresponse["ID"] = ticket.ID
+	flightresponse["UniqueId"] = ticket.UniqueId
+	flightresponse["Company"] = flight.Company
+	flightresponse["Seat"] = ticket.Seat
+	flightresponse["Class"] = ticket.Class
+	flightresponse["Price"] = ticket.Price
+	flightresponse["Status"] = flight.Status
+	if ticket.Pending {
+		flightresponse["Pending"] = true
+	}
+	if flight.Scheduled() {
+		flightresponse["Departure"] = flight.Departure
+	}
+	if !flight.EstimatedDeparture.IsZero() {
+		flightresponse["EstimatedDeparture"] = flight.EstimatedDeparture
+	}
+	return flightresponse
+}
+
+// BuyTicket handles the process of purchasing a ticket for an authenticated client.
+// It checks if the client is authorized, validates the reservation, updates the flight and client data,
+// and sends a response indicating success or failure.
+//
+// Parameters:
+//   - auth: A string representing the authentication token.
+//   - data: An interface containing the necessary data for purchasing a ticket.
+//   - conn: A net.Conn object representing the connection to the client.
+//
+// Return:
+//   - No return value.
+func BuyTicket(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+
+		return models.Response{
+			Error: "not authorized",
+		}
+	}
+
+	var buyTicket models.BuyTicket
+
+	jsonData, _ := json.Marshal(request.Data)
+	json.Unmarshal(jsonData, &buyTicket)
+
+	flight, err := dao.GetFlightDAO().FindById(buyTicket.FlightId)
+	if err != nil {
+		return models.Response{
+			Error:  "flight not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	success := false
+	id, conn := instance.FindConnectionByName(flight.Company)
+	if flight.Company != instance.ServerName && (id != "" && conn.IsOnline) {
+		// O ticket é criado pelo coordenador da transação distribuída
+		fare, exists := flight.Fare(buyTicket.Class)
+		success = exists && fare.Seats > 0 && instance.initiateBuy(flight.Company, *flight, buyTicket.Seat, buyTicket.Class, session.ClientID)
+	} else if flight.Company == instance.ServerName {
+		sold, err := instance.sellLocalSeat(buyTicket, session.ClientID)
+		if err != nil && err != errSeatsUnavailable {
+			return models.Response{
+				Error:  err.Error(),
+				Status: http.StatusConflict,
+			}
+		}
+		if success = err == nil; success {
+			instance.broadcast(*sold)
+		}
+	} else if buyTicket.Seat != "" {
+		// Somente o dono do voo atribui assentos
+		return models.Response{
+			Error:  "seat selection requires the flight owner to be online",
+			Status: http.StatusConflict,
+		}
+	} else if fare, _ := flight.Fare(""); buyTicket.Class != "" && buyTicket.Class != fare.Class {
+		// A cota em custódia contém somente assentos da primeira classe do voo
+		return models.Response{
+			Error:  "fare class selection requires the flight owner to be online",
+			Status: http.StatusConflict,
+		}
+	} else {
+		// O dono do voo está inacessível: a venda usa a cota de assentos recebida em custódia
+		success = instance.sellFromQuota(*flight, session.ClientID)
+	}
+
+	if success {
+		return models.Response{
+			Data: map[string]interface{}{
+				"msg": "success",
+			},
+			Status: http.StatusOK,
+		}
+	}
+	return models.Response{
+		Data: map[string]interface{}{
+			"Error": "not available seats",
+		},
+		Status: http.StatusNotAcceptable,
+	}
+
+}
+
+// sellLocalSeat sells to a client one seat of the fare class of a flight of this server, assigning the requested
+// seat or the first free one, and stores the ticket at the price quoted for it. The flight is read and updated
+// under the system lock, so concurrent sales never take the same seat.
+//
+// Return:
+//   - The updated flight, to be broadcasted after the lock is released.
+//   - errSeatsUnavailable if the fare class has no available seats, or the error of the seat assignment.
+func (s *System) sellLocalSeat(buyTicket models.BuyTicket, clientId uint) (*models.Flight, error) {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	flight, err := dao.GetFlightDAO().FindById(buyTicket.FlightId)
+	if err != nil {
+		return nil, err
+	}
+
+	price := quote(*flight, buyTicket.Class)
+	fare, err := flight.TakeSeats(buyTicket.Class, 1)
+	if err != nil {
+		return nil, errSeatsUnavailable
+	}
+
+	seat, err := flight.AssignSeat(buyTicket.Seat, fare.Class)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+	dao.GetTicketDAO().Insert(models.Ticket{
+		ClientId: clientId,
+		FlightId: buyTicket.FlightId,
+		Seat:     seat,
+		Class:    fare.Class,
+		Price:    price,
+	})
+	return flight, nil
+}
+
+// CancelBuy handles the cancellation of a ticket for an authenticated client.
+// It checks if the client is authorized, finds the ticket to be canceled, updates the flight and client data,
+// and sends a response indicating success or failure. Only tickets of the client can be cancelled; a ticket
+// of another client is reported as not found.
+//
+// Parameters:
+//   - auth: A string representing the authentication token. This is used to identify the client.
+//   - data: An interface containing the necessary data for canceling a ticket.
+//   - conn: A net.Conn object representing the connection to the client. This is used to send a response.
+//
+// Return:
+//   - No return value.
+func CancelBuy(id uint, request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+
+	if !exists {
+		return models.Response{
+			Error:  "not authorized",
+			Status: http.StatusUnauthorized,
+		}
+
+	}
+
+	// Somente o dono do ticket o cancela; um agente age pelo token do cliente recebido com o On-Behalf-Of
+	ticket, err := dao.GetTicketDAO().FindById(id)
+
+	if err != nil || ticket.ClientId != session.ClientID {
+		return models.Response{
+			Error:  "ticket not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	refund, success := cancelTicket(*ticket)
+	if !success {
+		// O ticket é mantido, e o cancelamento pode ser repetido quando o dono do voo estiver acessível
+		return models.Response{
+			Error:  "flight owner is unreachable",
+			Status: http.StatusServiceUnavailable,
+		}
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg":    "success",
+			"Refund": refund,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// cancelTicket gives the seat of a ticket back to its flight and removes the ticket. The seat is given back
+// by the company that owns the flight when it is another company: right away when it is online, or through
+// the outbox of its connection when it is offline.
+//
+// Parameters:
+//   - ticket: The ticket to be cancelled, with its flight.
+//
+// Return:
+//   - The amount refunded, following the refund rule of the fare class of the ticket.
+//   - true if the ticket was cancelled, false if the company that owns the flight didn't cancel it.
+func cancelTicket(ticket models.Ticket) (uint, bool) {
+	flight := ticket.Flight
+
+	// O assento de uma venda pendente da cota em custódia é o atribuído pelo dono à venda
+	request := models.SeatRequest{
+		FlightId: flight.UniqueId,
+		Seat:     ticket.Seat,
+		Class:    ticket.Class,
+	}
+	if ticket.Pending {
+		request.TicketId = ticket.UniqueId
+	}
+
+	success := false
+	connId, conn := instance.FindConnectionByName(flight.Company)
+	if flight.Company == instance.ServerName {
+		// O voo é lido novamente sob o lock, para não sobrescrever uma venda concorrente
+		instance.Lock.Lock()
+		current, err := dao.GetFlightDAO().FindById(ticket.FlightId)
+		if err == nil {
+			flight = *current
+			flight.ReleaseSeat(ticket.Seat)
+			flight.ReturnSeats(ticket.Class, 1)
+			instance.stampFlight(&flight)
+			dao.GetFlightDAO().Update(flight)
+		}
+		instance.Lock.Unlock()
+		if success = err == nil; success {
+			instance.broadcast(flight)
+		}
+	} else if connId != "" && conn.IsOnline {
+		success = instance.initiateCancel(flight.Company, request)
+	} else if connId != "" {
+		// Somente o dono altera o voo: o cancelamento fica na fila da conexão até que ela volte a ficar online,
+		// e a réplica local é atualizada pelo broadcast do dono
+		_, err := instance.enqueueMessage(connId, http.MethodDelete, "/server/ticket/cancel", request)
+		success = err == nil
+	}
+
+	if success {
+		dao.GetTicketDAO().Delete(ticket)
+	}
+
+	// O reembolso segue a regra da classe tarifária do ticket
+	refund := ticket.Price
+	if fare, exists := flight.Fare(ticket.Class); exists {
+		refund = fare.Refund(ticket.Price)
+	}
+
+	return refund, success
+}
@@ -0,0 +1,270 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// handleRefresh handles HTTP POST requests to exchange a refresh token for a new access token.
+// The refresh token is sent in the request body, so the endpoint doesn't require an access token.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleRefresh(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var refresh models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&refresh); err != nil {
+		returnResponse(w, r, invalidRequest())
+		return
+	}
+
+	returnResponse(w, r, RefreshTokens(refresh.Refresh))
+}
+
+// handleSessions handles the devices logged in by the authenticated client: GET lists them and
+// DELETE logs out the device given by the parameter id.
+//
+// Parameters:
+//   - w: http.ResponseWriter to write the HTTP response.
+//   - r: *http.Request to read the HTTP request.
+func handleSessions(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+	token := r.Header.Get("Authorization")
+
+	switch r.Method {
+	case http.MethodGet:
+		returnResponse(w, r, GetSessions(models.Request{Auth: token}))
+	case http.MethodDelete:
+		returnResponse(w, r, RevokeSession(r.URL.Query().Get("id"), models.Request{Auth: token}))
+	default:
+		http.Error(w, "only GET, DELETE allowed", http.StatusMethodNotAllowed)
+		return
+	}
+}
+
+// issueTokens logs a device of a client in. The refresh token of the device is stored and the access token
+// is signed with its id (sid), so that the device is identified without a lookup. When the client already
+// has the maximum number of devices, the least recently used ones are logged out.
+//
+// Parameters:
+//   - client: The client, whose role is signed in the access token.
+//   - home: The home company of a federated client, empty for the clients of this company.
+//   - device: A description of the device, shown in the list of sessions.
+//
+// Return:
+//   - The data of the response with the tokens.
+//   - An error if the tokens could not be created.
+func issueTokens(client *models.Client, home string, device string) (map[string]interface{}, error) {
+	now := time.Now()
+
+	if tokens, err := dao.GetRefreshTokenDAO().FindByClient(client.ID, now); err == nil {
+		for len(tokens) >= cfg.MaxDevices {
+			revokeToken(tokens[0])
+			tokens = tokens[1:]
+		}
+	}
+
+	secret, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh := models.RefreshToken{
+		TokenId:    uuid.New().String(),
+		ClientId:   client.ID,
+		SecretHash: utils.HashString(secret),
+		Device:     device,
+		Home:       home,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(cfg.RefreshTokenTTL()),
+	}
+	if err := dao.GetRefreshTokenDAO().Insert(refresh); err != nil {
+		return nil, err
+	}
+
+	return tokenData(refresh, client.EffectiveRole(), secret, now)
+}
+
+// RefreshTokens exchanges a refresh token for a new access token. The secret of the refresh token is replaced
+// on every use and its expiration is extended, so a device in use stays logged in. Presenting an old secret
+// logs the device out, since it means the token was copied. The new access token has the current role of the client.
+//
+// Parameters:
+//   - token: The refresh token, in the format <id>.<secret>.
+//
+// Return:
+//   - A response with the new access and refresh tokens, or a 401 Unauthorized when the refresh token is not valid.
+func RefreshTokens(token string) models.Response {
+	now := time.Now()
+
+	tokenId, secret, _ := strings.Cut(token, ".")
+	refresh, err := dao.GetRefreshTokenDAO().FindByTokenId(tokenId)
+	if err != nil || secret == "" {
+		return notAuthorized()
+	}
+
+	if !refresh.ExpiresAt.After(now) {
+		revokeToken(*refresh)
+		return notAuthorized()
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashString(secret)), []byte(refresh.SecretHash)) != 1 {
+		log.Printf("Reused refresh token of client %d, logging the device out", refresh.ClientId)
+		revokeToken(*refresh)
+		return notAuthorized()
+	}
+
+	client, err := dao.GetClientDAO().FindById(refresh.ClientId)
+	if err != nil {
+		revokeToken(*refresh)
+		return notAuthorized()
+	}
+
+	if secret, err = utils.RandomToken(32); err != nil {
+		return internalError("failed to create token")
+	}
+	refresh.SecretHash = utils.HashString(secret)
+	refresh.LastUsedAt = now
+	refresh.ExpiresAt = now.Add(cfg.RefreshTokenTTL())
+	if err := dao.GetRefreshTokenDAO().Update(*refresh); err != nil {
+		return internalError("failed to update token")
+	}
+
+	data, err := tokenData(*refresh, client.EffectiveRole(), secret, now)
+	if err != nil {
+		return internalError("failed to create token")
+	}
+
+	return models.Response{
+		Data:   data,
+		Status: http.StatusOK,
+	}
+}
+
+// GetSessions lists the devices logged in by the authenticated client.
+func GetSessions(request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+	if !exists {
+		return notAuthorized()
+	}
+
+	tokens, err := dao.GetRefreshTokenDAO().FindByClient(session.ClientID, time.Now())
+	if err != nil {
+		return internalError("failed to find sessions")
+	}
+
+	sessions := make([]map[string]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, map[string]interface{}{
+			"Id":         token.TokenId,
+			"Device":     token.Device,
+			"LastUsedAt": token.LastUsedAt,
+			"ExpiresAt":  token.ExpiresAt,
+			"Current":    token.TokenId == session.ID.String(),
+		})
+	}
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"sessions": sessions,
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// RevokeSession logs out a device of the authenticated client.
+//
+// Parameters:
+//   - id: The id of the session of the device, as listed by GetSessions.
+//   - request: The request with the access token.
+func RevokeSession(id string, request models.Request) models.Response {
+	session, exists := SessionIfExists(request.Auth)
+	if !exists {
+		return notAuthorized()
+	}
+
+	token, err := dao.GetRefreshTokenDAO().FindByTokenId(id)
+	if err != nil || token.ClientId != session.ClientID {
+		return models.Response{
+			Error:  "session not found",
+			Status: http.StatusNotFound,
+		}
+	}
+
+	revokeToken(*token)
+
+	return models.Response{
+		Data: map[string]interface{}{
+			"msg": "session revoked",
+		},
+		Status: http.StatusOK,
+	}
+}
+
+// revokeSessions logs out the devices of a client, except the one given to be kept.
+func revokeSessions(clientId uint, keep string) {
+	tokens, err := dao.GetRefreshTokenDAO().FindByClient(clientId, time.Now())
+	if err != nil {
+		return
+	}
+	for _, token := range tokens {
+		if token.TokenId != keep {
+			revokeToken(token)
+		}
+	}
+}
+
+// revokeToken removes the refresh token of a device and the state kept in memory for it. The access tokens
+// already given to the device stay valid until they expire.
+func revokeToken(token models.RefreshToken) {
+	dao.GetRefreshTokenDAO().Delete(token)
+	if id, err := uuid.Parse(token.TokenId); err == nil {
+		dao.GetSessionDAO().Delete(&models.Session{ID: id})
+	}
+}
+
+// tokenData signs an access token for the device of the refresh token and builds the data of the response.
+func tokenData(refresh models.RefreshToken, role string, secret string, now time.Time) (map[string]interface{}, error) {
+	ttl := cfg.AccessTokenTTL()
+	access, err := signer.Sign(models.AccessClaims{
+		Subject:   refresh.ClientId,
+		Session:   refresh.TokenId,
+		Issuer:    cfg.Company,
+		Home:      refresh.Home,
+		Role:      role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"token":     access,
+		"refresh":   refresh.TokenId + "." + secret,
+		"expiresIn": int(ttl.Seconds()),
+	}, nil
+}
+
+func notAuthorized() models.Response {
+	return models.Response{
+		Error:  "not authorized",
+		Code:   models.CodeNotAuthorized,
+		Status: http.StatusUnauthorized,
+	}
+}
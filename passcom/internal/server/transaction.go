@@ -0,0 +1,241 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+func (s *System) HandleServerTicketPurchase(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	s.IncrementClock()
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	err := json.NewDecoder(r.Body).Decode(&msg)
+
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Uma mensagem retransmitida recebe a resposta da primeira, sem decrementar o assento novamente
+	s.withIdempotency(w, messageKey(msg), requestHash(msg.Body), func(w http.ResponseWriter) {
+		s.purchaseSeat(w, msg)
+	})
+}
+
+// purchaseSeat takes a seat of the flight and fare class given by the models.SeatRequest in the body of the message.
+// The purchase is a two-phase commit decided in a single step: the seat is reserved as in the prepare phase,
+// under a participant transaction identified by the ID of the message, and committed right away. The requested
+// seat, or the first free one if none is requested, is assigned and returned in the response with the price
+// quoted for it. Only flights of this server are sold. The caller must hold the system lock.
+func (s *System) purchaseSeat(w http.ResponseWriter, msg models.Message) {
+	to := msg.To
+	var request models.SeatRequest
+
+	if err := decodeBody(msg.Body, &request); err != nil || request.FlightId == "" {
+		http.Error(w, "Invalid purchase data", http.StatusBadRequest)
+		return
+	}
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(request.FlightId)
+	if err != nil || flight.Company != s.ServerName {
+		http.Error(w, "Flight not found", http.StatusNotFound)
+		return
+	}
+
+	transactionId := msg.Id
+	if transactionId == "" {
+		transactionId = uuid.NewString()
+	}
+
+	// Uma compra já decidida, mesmo fora da janela de idempotência, recebe a mesma decisão
+	participant, err := dao.GetTransactionDAO().FindByTransactionId(transactionId)
+	if err != nil {
+		reserved, err := s.reserveParticipantSeat(msg.From, models.Transaction{
+			TransactionId: transactionId,
+			Type:          models.TypePurchase,
+			FlightId:      request.FlightId,
+			Class:         request.Class,
+			Seat:          request.Seat,
+		})
+		if err != nil {
+			http.Error(w, "Failed to reserve seat", http.StatusInternalServerError)
+			return
+		}
+		participant = &reserved
+	}
+
+	switch participant.Status {
+	case models.REJECTED:
+		http.Error(w, "Seat not available", http.StatusConflict)
+		return
+	case models.PENDING:
+		if err := s.commitParticipant(participant); err != nil {
+			http.Error(w, "Failed to commit purchase", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	request.Class = participant.Class
+	request.Seat = participant.Seat
+	request.Price = participant.Price
+
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), to, s.VectorClock, request)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+}
+
+func (s *System) HandleServerTicketCancel(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	s.IncrementClock()
+
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Only DELETE allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var msg models.Message
+	err := json.NewDecoder(r.Body).Decode(&msg)
+
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	// Uma mensagem retransmitida recebe a resposta da primeira, sem incrementar o assento novamente
+	s.withIdempotency(w, messageKey(msg), requestHash(msg.Body), func(w http.ResponseWriter) {
+		s.cancelSeat(w, msg)
+	})
+}
+
+// cancelSeat gives back a seat of the flight and fare class given by the models.SeatRequest in the body of
+// the message, freeing its assigned seat. For a pending sale from an escrow quota, the seat freed is the one
+// assigned to the sale, if any. Only flights of this server are changed. The caller must hold the system lock.
+func (s *System) cancelSeat(w http.ResponseWriter, msg models.Message) {
+	to := msg.To
+	var request models.SeatRequest
+
+	if err := decodeBody(msg.Body, &request); err != nil || request.FlightId == "" {
+		http.Error(w, "Invalid cancellation data", http.StatusBadRequest)
+		return
+	}
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(request.FlightId)
+	if err != nil || flight.Company != s.ServerName {
+		http.Error(w, "Flight not found", http.StatusNotFound)
+		return
+	}
+
+	seat := request.Seat
+	if request.TicketId != "" {
+		if sale, err := dao.GetQuotaSaleDAO().FindByTicketId(request.TicketId); err == nil {
+			seat = sale.Seat
+			sale.Cancelled = true
+			dao.GetQuotaSaleDAO().Update(*sale)
+		} else {
+			// A venda ainda não foi informada: o registro cancelado impede que ela receba um assento depois
+			dao.GetQuotaSaleDAO().Insert(models.QuotaSale{
+				FlightId:  flight.UniqueId,
+				Holder:    msg.From,
+				TicketId:  request.TicketId,
+				Class:     request.Class,
+				Cancelled: true,
+			})
+		}
+	}
+
+	flight.ReleaseSeat(seat)
+	flight.ReturnSeats(request.Class, 1)
+	s.stampFlight(flight)
+	dao.GetFlightDAO().Update(*flight)
+
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), to, s.VectorClock, "")
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, http.StatusOK)
+
+	s.broadcast(*flight)
+}
+
+func (s *System) initiateCancel(company string, request models.SeatRequest) bool {
+	// Localiza o endereço do servidor da companhia responsável
+	id, conn := s.FindConnectionByName(company)
+	if id == "" {
+		log.Printf("Connection not found for company: %s", company)
+		return false
+	}
+
+	url := URL_PREFIX + conn.Address + ":" + conn.Port + "/server/ticket/cancel"
+
+	// Cria a mensagem de cancelamento com UniqueId do voo, o assento a liberar e a sua classe tarifária
+	requestMsg, err := models.CreateMessage(s.ServerId.String(), id, s.VectorClock, request)
+	if err != nil {
+		log.Printf("Error creating request message for cancellation: %v", err)
+		return false
+	}
+
+	// Converte a mensagem para JSON
+	jsonData, err := json.Marshal(requestMsg)
+	if err != nil {
+		log.Printf("Error encoding cancellation message to JSON: %v", err)
+		return false
+	}
+
+	// Envia a solicitação de cancelamento ao servidor da companhia. Em caso de falha, a mesma mensagem
+	// é reenviada, para que o servidor da companhia a deduplique pelo seu ID
+	client := &http.Client{Timeout: CONNECTION_TIMEOUT}
+	var resp *http.Response
+	for attempt := 1; attempt <= SERVER_REQUEST_RETRIES && resp == nil; attempt++ {
+		// Cria uma requisição HTTP DELETE
+		req, err := http.NewRequest(http.MethodDelete, url, bytes.NewBuffer(jsonData))
+		if err != nil {
+			log.Printf("Error creating cancel request: %v", err)
+			return false
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err = client.Do(req)
+		if err != nil {
+			log.Printf("Error sending cancellation request (attempt %d): %v", attempt, err)
+		}
+	}
+	if resp == nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	// Verifica o status da resposta
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Cancellation request failed with status: %s", resp.Status)
+		return false
+	}
+
+	log.Printf("Cancellation request successful for flight %s on company %s", request.FlightId, company)
+	return true
+}
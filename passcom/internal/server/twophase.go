@@ -0,0 +1,719 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HandlePrepare handles the first phase of a distributed purchase on the participant side.
+// The participant reserves one seat of the fare class of its own flight, assigning the seat requested by the
+// coordinator or the first free one, and durably records the transaction as PENDING, voting "yes" with 200 OK, the assigned seat
+// and the price quoted for it. If the seat can't be reserved, the transaction is recorded as REJECTED
+// and the participant votes "no" with 409 Conflict.
+//
+// Repeated prepares for the same TransactionId are answered with the vote already recorded.
+func (s *System) HandlePrepare(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, transaction, err := decodeTransactionMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	s.IncrementClock()
+
+	if stored, err := dao.GetTransactionDAO().FindByTransactionId(transaction.TransactionId); err == nil {
+		s.replyVote(w, msg.From, *stored)
+		return
+	}
+
+	participant, err := s.reserveParticipantSeat(msg.From, *transaction)
+	if err != nil {
+		http.Error(w, "Failed to reserve seat", http.StatusInternalServerError)
+		return
+	}
+
+	s.replyVote(w, msg.From, participant)
+}
+
+// HandleCommit handles the COMMIT decision of the coordinator. The seat reserved during the
+// prepare phase becomes definitive and the new state of the flight is broadcasted to the peers.
+func (s *System) HandleCommit(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, transaction, err := decodeTransactionMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	s.IncrementClock()
+
+	stored, err := dao.GetTransactionDAO().FindByTransactionId(transaction.TransactionId)
+	if err != nil {
+		http.Error(w, "Transaction not found", http.StatusNotFound)
+		return
+	}
+
+	switch stored.Status {
+	case models.REJECTED:
+		http.Error(w, "Transaction already aborted", http.StatusConflict)
+		return
+	case models.PENDING:
+		if err := s.commitParticipant(stored); err != nil {
+			http.Error(w, "Failed to commit transaction", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	s.replyTransaction(w, msg.From, *stored, http.StatusOK)
+}
+
+// HandleAbort handles the ABORT decision of the coordinator, releasing the seat reserved
+// during the prepare phase. An abort for an unknown transaction is recorded as REJECTED,
+// so a delayed prepare for it will be refused.
+func (s *System) HandleAbort(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "Only POST allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, transaction, err := decodeTransactionMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	s.IncrementClock()
+
+	stored, err := dao.GetTransactionDAO().FindByTransactionId(transaction.TransactionId)
+	if err != nil {
+		rejected := models.Transaction{
+			TransactionId: transaction.TransactionId,
+			Type:          transaction.Type,
+			FlightId:      transaction.FlightId,
+			Role:          models.RoleParticipant,
+			Coordinator:   msg.From,
+			Participant:   s.ServerId.String(),
+			Status:        models.REJECTED,
+		}
+		dao.GetTransactionDAO().Insert(rejected)
+		s.replyTransaction(w, msg.From, rejected, http.StatusOK)
+		return
+	}
+
+	switch stored.Status {
+	case models.COMMITED:
+		http.Error(w, "Transaction already committed", http.StatusConflict)
+		return
+	case models.PENDING:
+		if err := s.abortParticipant(stored); err != nil {
+			http.Error(w, "Failed to abort transaction", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	s.replyTransaction(w, msg.From, *stored, http.StatusOK)
+}
+
+// HandleTransactionStatus answers a participant asking for the final decision of a transaction
+// coordinated by this server. Unknown transactions are presumed aborted.
+func (s *System) HandleTransactionStatus(w http.ResponseWriter, r *http.Request) {
+	allowCrossOrigin(w, r)
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "Only GET allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	msg, transaction, err := decodeTransactionMessage(r)
+	if err != nil {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	s.Lock.RLock()
+	defer s.Lock.RUnlock()
+
+	decision := models.Transaction{
+		TransactionId: transaction.TransactionId,
+		Status:        models.REJECTED,
+	}
+
+	if stored, err := dao.GetTransactionDAO().FindByTransactionId(transaction.TransactionId); err == nil {
+		decision = *stored
+	}
+
+	s.replyTransaction(w, msg.From, decision, http.StatusOK)
+}
+
+// initiateBuy coordinates the purchase of a flight owned by another company through a two-phase commit.
+//
+// Parameters:
+//   - company: The name of the company that owns the flight.
+//   - flight: The flight being purchased.
+//   - seat: The seat chosen by the client, or empty for the first free seat.
+//   - class: The fare class chosen by the client, or empty for the first class of the flight.
+//   - clientId: The ID of the local client buying the ticket.
+//
+// Return:
+//   - true if the purchase was committed, false otherwise.
+func (s *System) initiateBuy(company string, flight models.Flight, seat, class string, clientId uint) bool {
+	if err := s.initiateItinerary([]models.Flight{flight}, []string{seat}, []string{class}, clientId); err != nil {
+		log.Printf("Purchase of flight %s on company %s failed: %v", flight.UniqueId, company, err)
+		return false
+	}
+
+	log.Printf("Purchase committed for flight %s on company %s", flight.UniqueId, company)
+	return true
+}
+
+// initiateItinerary coordinates the purchase of one or more flights as a single two-phase commit.
+// Every leg is stored as a PENDING transaction and prepared on the server that owns its flight,
+// in order. If every participant votes "yes", all tickets are created at once and the legs are
+// marked as COMMITED before the decision is sent; otherwise every leg is REJECTED and the seats
+// already reserved are released by the abort decision.
+//
+// Decisions that couldn't be delivered are retried by resolveTransactions.
+//
+// Parameters:
+//   - flights: The flights being purchased, one per leg.
+//   - seats: The seats chosen for the legs, in order. Legs without a chosen seat get the first free one.
+//   - classes: The fare classes chosen for the legs, in order. Legs without a chosen class use the first class of their flight.
+//   - clientId: The ID of the local client buying the tickets.
+//
+// Return:
+//   - An error describing the leg that could not be reserved, or nil if the purchase was committed.
+func (s *System) initiateItinerary(flights []models.Flight, seats, classes []string, clientId uint) error {
+	itineraryId, err := uuid.NewV7()
+	if err != nil {
+		return fmt.Errorf("error generating itinerary ID: %v", err)
+	}
+
+	s.pending.Store(itineraryId.String(), true)
+	defer s.pending.Delete(itineraryId.String())
+
+	legs := make([]models.Transaction, 0, len(flights))
+	var failure error
+
+	for i, flight := range flights {
+		seat, class := "", ""
+		if i < len(seats) {
+			seat = seats[i]
+		}
+		if i < len(classes) {
+			class = classes[i]
+		}
+
+		transaction, err := s.prepareLeg(itineraryId.String(), flight, seat, class, clientId)
+		if transaction != nil {
+			legs = append(legs, *transaction)
+		}
+		if err != nil {
+			failure = fmt.Errorf("leg %d (flight %d) not available: %v", i+1, flight.ID, err)
+			break
+		}
+	}
+
+	if failure != nil {
+		for _, transaction := range legs {
+			transaction.Status = models.REJECTED
+			dao.GetTransactionDAO().Update(transaction)
+			s.AddTransactionToLog(time.Now(), transaction, models.REJECTED)
+			s.sendDecision(transaction)
+		}
+		return failure
+	}
+
+	tickets := make([]models.Ticket, len(legs))
+	for i, transaction := range legs {
+		tickets[i] = models.Ticket{
+			ClientId: clientId,
+			FlightId: flights[i].ID,
+			UniqueId: transaction.TransactionId,
+			Seat:     transaction.Seat,
+			Class:    transaction.Class,
+			Price:    transaction.Price,
+		}
+	}
+
+	if err := dao.GetTicketDAO().InsertAll(tickets); err != nil {
+		for _, transaction := range legs {
+			transaction.Status = models.REJECTED
+			dao.GetTransactionDAO().Update(transaction)
+			s.sendDecision(transaction)
+		}
+		return fmt.Errorf("error creating tickets: %v", err)
+	}
+
+	for _, transaction := range legs {
+		transaction.Status = models.COMMITED
+		dao.GetTransactionDAO().Update(transaction)
+		s.AddTransactionToLog(time.Now(), transaction, models.COMMITED)
+		s.sendDecision(transaction)
+	}
+
+	return nil
+}
+
+// prepareLeg stores a PENDING transaction for one leg of a purchase and runs its prepare phase.
+// Flights of this server are reserved locally; flights of other companies are prepared on their owner,
+// which must be connected and online. The owner assigns the seat and quotes its price, which are stored in the transaction.
+//
+// Return:
+//   - The stored transaction, or nil if it couldn't be stored.
+//   - An error if the participant didn't vote "yes".
+func (s *System) prepareLeg(itineraryId string, flight models.Flight, seat, class string, clientId uint) (*models.Transaction, error) {
+	participant := s.ServerId.String()
+	var conn *models.Connection
+
+	fare, exists := flight.Fare(class)
+	if !exists {
+		return nil, fmt.Errorf("fare class %s not found", class)
+	}
+
+	if flight.Company != s.ServerName {
+		id, c := s.FindConnectionByName(flight.Company)
+		if id == "" || !c.IsOnline {
+			return nil, fmt.Errorf("company %s is offline", flight.Company)
+		}
+		participant, conn = id, c
+	}
+
+	transactionId, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := models.Transaction{
+		TransactionId: transactionId.String(),
+		ItineraryId:   itineraryId,
+		Type:          models.TypePurchase,
+		FlightId:      flight.UniqueId,
+		Seat:          seat,
+		Class:         fare.Class,
+		ClientId:      clientId,
+		Role:          models.RoleCoordinator,
+		Coordinator:   s.ServerId.String(),
+		Participant:   participant,
+		Status:        models.PENDING,
+	}
+
+	if err := dao.GetTransactionDAO().Insert(transaction); err != nil {
+		return nil, err
+	}
+	s.AddTransactionToLog(time.Now(), transaction, models.PENDING)
+
+	if conn == nil {
+		if err := s.reserveLocalSeat(&transaction); err != nil {
+			return &transaction, err
+		}
+	} else {
+		status, response, err := s.sendServerMessage(participant, *conn, http.MethodPost, "/server/ticket/prepare", transaction)
+		if err != nil {
+			return &transaction, err
+		}
+		if status != http.StatusOK {
+			return &transaction, fmt.Errorf("participant voted no (status %d)", status)
+		}
+
+		var vote models.Transaction
+		if response != nil && decodeBody(response.Body, &vote) == nil {
+			transaction.Seat = vote.Seat
+			transaction.Price = vote.Price
+		}
+	}
+
+	transaction.Prepared = true
+	dao.GetTransactionDAO().Update(transaction)
+
+	return &transaction, nil
+}
+
+// reserveLocalSeat takes one seat of the fare class of a flight of this server for a leg being prepared, storing
+// in the transaction the assigned seat, the requested one or the first free seat if none is requested, and its price.
+//
+// Return:
+//   - An error if there are no seats available in the class or the requested seat is taken.
+func (s *System) reserveLocalSeat(transaction *models.Transaction) error {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(transaction.FlightId)
+	if err != nil {
+		return err
+	}
+
+	price := quote(*flight, transaction.Class)
+	fare, err := flight.TakeSeats(transaction.Class, 1)
+	if err != nil {
+		return err
+	}
+
+	seat, err := flight.AssignSeat(transaction.Seat, fare.Class)
+	if err != nil {
+		return err
+	}
+
+	s.stampFlight(flight)
+	if err := dao.GetFlightDAO().Update(*flight); err != nil {
+		return err
+	}
+
+	transaction.Seat, transaction.Price = seat, price
+	return nil
+}
+
+// applyLocalDecision applies the decision of a leg whose flight belongs to this server:
+// a committed seat is broadcasted, a rejected one is released if it had been reserved.
+func (s *System) applyLocalDecision(transaction models.Transaction) error {
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(transaction.FlightId)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Status == models.REJECTED {
+		if !transaction.Prepared {
+			return nil
+		}
+		flight.ReleaseSeat(transaction.Seat)
+		flight.ReturnSeats(transaction.Class, 1)
+		s.stampFlight(flight)
+		if err := dao.GetFlightDAO().Update(*flight); err != nil {
+			return err
+		}
+	}
+
+	s.broadcast(*flight)
+	return nil
+}
+
+// sendDecision sends the final decision of a coordinated transaction to its participant and
+// marks it as acknowledged when the participant confirms it.
+func (s *System) sendDecision(transaction models.Transaction) {
+	if transaction.Participant == s.ServerId.String() {
+		if err := s.applyLocalDecision(transaction); err != nil {
+			log.Printf("Decision of transaction %s not applied: %v", transaction.TransactionId, err)
+			return
+		}
+		transaction.Acknowledged = true
+		dao.GetTransactionDAO().Update(transaction)
+		return
+	}
+
+	s.Lock.RLock()
+	conn, exists := s.Connections[transaction.Participant]
+	s.Lock.RUnlock()
+
+	if !exists || !conn.IsOnline {
+		log.Printf("Participant of transaction %s is unreachable, decision will be retried", transaction.TransactionId)
+		return
+	}
+
+	path := "/server/ticket/abort"
+	if transaction.Status == models.COMMITED {
+		path = "/server/ticket/commit"
+	}
+
+	status, _, err := s.sendServerMessage(transaction.Participant, conn, http.MethodPost, path, transaction)
+	if err != nil || status != http.StatusOK {
+		log.Printf("Decision of transaction %s not delivered (status %d, err %v)", transaction.TransactionId, status, err)
+		return
+	}
+
+	transaction.Acknowledged = true
+	dao.GetTransactionDAO().Update(transaction)
+}
+
+// resolveTransactions drives every unresolved transaction towards its final state.
+// It is executed once when the server starts, recovering in-doubt transactions left by a crash,
+// and periodically afterwards to retry decisions that weren't delivered.
+//
+// As coordinator, a transaction without decision is committed if its ticket was already created
+// and aborted otherwise; decided transactions have their decision resent. Transactions whose
+// itinerary is still being coordinated by this process are skipped.
+// As participant, the coordinator is asked for the decision of every PENDING transaction.
+func (s *System) resolveTransactions() {
+	transactions, err := dao.GetTransactionDAO().FindUnresolved()
+	if err != nil {
+		return
+	}
+
+	for _, transaction := range transactions {
+		switch transaction.Role {
+		case models.RoleCoordinator:
+			if transaction.Status == models.PENDING {
+				if _, running := s.pending.Load(transaction.ItineraryId); running {
+					continue
+				}
+
+				transaction.Status = models.REJECTED
+				if _, err := dao.GetTicketDAO().FindByUniqueId(transaction.TransactionId); err == nil {
+					transaction.Status = models.COMMITED
+				}
+				dao.GetTransactionDAO().Update(transaction)
+				s.AddTransactionToLog(time.Now(), transaction, transaction.Status)
+			}
+			s.sendDecision(transaction)
+
+		case models.RoleParticipant:
+			s.queryDecision(transaction)
+		}
+	}
+}
+
+// queryDecision asks the coordinator of an in-doubt transaction for its final decision
+// and applies it locally.
+func (s *System) queryDecision(transaction models.Transaction) {
+	s.Lock.RLock()
+	conn, exists := s.Connections[transaction.Coordinator]
+	s.Lock.RUnlock()
+
+	if !exists || !conn.IsOnline {
+		return
+	}
+
+	status, response, err := s.sendServerMessage(transaction.Coordinator, conn, http.MethodGet, "/server/ticket/status", transaction)
+	if err != nil || status != http.StatusOK || response == nil {
+		log.Printf("Could not query decision of transaction %s (status %d, err %v)", transaction.TransactionId, status, err)
+		return
+	}
+
+	var decision models.Transaction
+	if err := decodeBody(response.Body, &decision); err != nil {
+		log.Printf("Invalid decision for transaction %s: %v", transaction.TransactionId, err)
+		return
+	}
+
+	s.Lock.Lock()
+	defer s.Lock.Unlock()
+
+	switch decision.Status {
+	case models.COMMITED:
+		s.commitParticipant(&transaction)
+	case models.REJECTED:
+		s.abortParticipant(&transaction)
+	}
+}
+
+// retryTransactions periodically resolves pending transactions.
+func (s *System) retryTransactions() {
+	s.resolveTransactions()
+
+	ticker := time.NewTicker(TRANSACTION_RETRY_TIMER)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.resolveTransactions()
+	}
+}
+
+// reserveParticipantSeat runs the prepare phase of a purchase on the participant side: one seat of the fare class
+// of its own flight is reserved, assigning the seat requested by the coordinator or the first free one, and the
+// transaction is durably recorded as PENDING with the assigned seat and the price quoted for it. If the seat can't
+// be reserved, the transaction is recorded as REJECTED. The caller must hold the system lock.
+//
+// Return:
+//   - The participant transaction, PENDING or REJECTED.
+//   - An error if the reservation couldn't be stored.
+func (s *System) reserveParticipantSeat(coordinator string, transaction models.Transaction) (models.Transaction, error) {
+	participant := models.Transaction{
+		TransactionId: transaction.TransactionId,
+		Type:          transaction.Type,
+		FlightId:      transaction.FlightId,
+		Role:          models.RoleParticipant,
+		Coordinator:   coordinator,
+		Participant:   s.ServerId.String(),
+		Status:        models.PENDING,
+	}
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(transaction.FlightId)
+	if err == nil && flight.Company == s.ServerName && transaction.Type == models.TypePurchase {
+		var fare models.Fare
+		participant.Price = quote(*flight, transaction.Class)
+		if fare, err = flight.TakeSeats(transaction.Class, 1); err == nil {
+			participant.Class = fare.Class
+			participant.Seat, err = flight.AssignSeat(transaction.Seat, fare.Class)
+		}
+	}
+	if err != nil || flight.Company != s.ServerName || transaction.Type != models.TypePurchase {
+		participant.Status = models.REJECTED
+		dao.GetTransactionDAO().Insert(participant)
+		return participant, nil
+	}
+
+	s.stampFlight(flight)
+	if err := dao.GetFlightDAO().Update(*flight); err != nil {
+		return participant, err
+	}
+
+	if err := dao.GetTransactionDAO().Insert(participant); err != nil {
+		flight.ReleaseSeat(participant.Seat)
+		flight.ReturnSeats(participant.Class, 1)
+		dao.GetFlightDAO().Update(*flight)
+		return participant, err
+	}
+
+	return participant, nil
+}
+
+// commitParticipant makes the reservation of a PENDING participant transaction definitive.
+// The caller must hold the system lock.
+func (s *System) commitParticipant(transaction *models.Transaction) error {
+	transaction.Status = models.COMMITED
+	if err := dao.GetTransactionDAO().Update(*transaction); err != nil {
+		return err
+	}
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(transaction.FlightId)
+	if err != nil {
+		return err
+	}
+
+	s.broadcast(*flight)
+	return nil
+}
+
+// abortParticipant releases the seat reserved by a PENDING participant transaction.
+// The caller must hold the system lock.
+func (s *System) abortParticipant(transaction *models.Transaction) error {
+	flight, err := dao.GetFlightDAO().FindByUniqueId(transaction.FlightId)
+	if err != nil {
+		return err
+	}
+
+	flight.ReleaseSeat(transaction.Seat)
+	flight.ReturnSeats(transaction.Class, 1)
+	s.stampFlight(flight)
+	if err := dao.GetFlightDAO().Update(*flight); err != nil {
+		return err
+	}
+
+	transaction.Status = models.REJECTED
+	return dao.GetTransactionDAO().Update(*transaction)
+}
+
+// replyVote answers a prepare message with the vote recorded for the transaction.
+// COMMITED and PENDING transactions are answered with 200 OK, REJECTED ones with 409 Conflict.
+func (s *System) replyVote(w http.ResponseWriter, to string, transaction models.Transaction) {
+	status := http.StatusOK
+	if transaction.Status == models.REJECTED {
+		status = http.StatusConflict
+	}
+
+	s.replyTransaction(w, to, transaction, status)
+}
+
+// replyTransaction answers a transaction message with the state recorded for the transaction.
+func (s *System) replyTransaction(w http.ResponseWriter, to string, transaction models.Transaction, status int) {
+	responseMsg, err := models.CreateMessage(s.ServerId.String(), to, s.VectorClock, transaction)
+	if err != nil {
+		http.Error(w, "Failed to create response message", http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendJSONResponse(w, responseMsg, status)
+}
+
+// sendServerMessage sends a message with the given body to another server and decodes its response message.
+//
+// Return:
+//   - The HTTP status code of the response.
+//   - The decoded response message, or nil if it couldn't be decoded.
+//   - An error if the request couldn't be sent.
+func (s *System) sendServerMessage(id string, conn models.Connection, method string, path string, body interface{}) (int, *models.Message, error) {
+	requestMsg, err := models.CreateMessage(s.ServerId.String(), id, s.VectorClock, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	jsonData, err := json.Marshal(requestMsg)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	url := URL_PREFIX + conn.Address + ":" + conn.Port + path
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: CONNECTION_TIMEOUT}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	var responseMsg models.Message
+	if err := json.NewDecoder(resp.Body).Decode(&responseMsg); err != nil {
+		return resp.StatusCode, nil, nil
+	}
+
+	return resp.StatusCode, &responseMsg, nil
+}
+
+// decodeTransactionMessage decodes a message whose body is a models.Transaction.
+func decodeTransactionMessage(r *http.Request) (*models.Message, *models.Transaction, error) {
+	var msg models.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		return nil, nil, err
+	}
+
+	var transaction models.Transaction
+	if err := decodeBody(msg.Body, &transaction); err != nil {
+		return nil, nil, err
+	}
+
+	if transaction.TransactionId == "" {
+		return nil, nil, fmt.Errorf("missing transaction ID")
+	}
+
+	return &msg, &transaction, nil
+}
+
+// decodeBody converts the generic body of a message into the given type.
+func decodeBody(body interface{}, target interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(jsonData, target)
+}
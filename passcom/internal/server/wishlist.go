@@ -1,11 +1,11 @@
 package server
 
 import (
-	"boreal/internal/dao"
-	"boreal/internal/models"
 	"encoding/json"
 	"log"
 	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
 	"strconv"
 )
 
@@ -90,6 +90,8 @@ func GetWishlist(req models.Request) models.Response {
 		}
 	}
 
+	session = deviceState(session)
+
 	return models.Response{
 		Data: map[string]interface{}{
 			"Wishes": session.Wishlist,
@@ -107,6 +109,8 @@ func DeleteFromWishlist(id uint, req models.Request) models.Response {
 		}
 	}
 
+	session = deviceState(session)
+
 	for i, w := range session.Wishlist {
 		if w.ID == id {
 			// Remover preservando a ordem
@@ -140,6 +144,8 @@ func AddToWishlist(req models.Request) models.Response {
 		}
 	}
 
+	session = deviceState(session)
+
 	var addWish models.WishlistOperation
 
 	jsonData, _ := json.Marshal(req.Data)
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashString returns the hexadecimal SHA-256 hash of the given string.
+func HashString(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// MerkleTree builds a binary hash tree over the given leaf hashes.
+// The tree is returned level by level, from the leaves to the root. A node without a sibling
+// is promoted unchanged to the next level.
+func MerkleTree(leaves []string) [][]string {
+	if len(leaves) == 0 {
+		return [][]string{{HashString("")}}
+	}
+
+	tree := [][]string{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]string, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				next = append(next, HashString(level[i]+level[i+1]))
+			} else {
+				next = append(next, level[i])
+			}
+		}
+		tree = append(tree, next)
+		level = next
+	}
+
+	return tree
+}
+
+// MerkleDiff compares two trees built by MerkleTree and returns the indexes of the leaves that differ,
+// descending only into the subtrees whose hashes differ. If the trees don't have the same shape,
+// every leaf is considered different.
+func MerkleDiff(a [][]string, b [][]string) []int {
+	diff := make([]int, 0)
+
+	if len(a) != len(b) || len(a) == 0 || len(a[0]) != len(b[0]) {
+		leaves := 0
+		for _, tree := range [][][]string{a, b} {
+			if len(tree) > 0 && len(tree[0]) > leaves {
+				leaves = len(tree[0])
+			}
+		}
+		for i := 0; i < leaves; i++ {
+			diff = append(diff, i)
+		}
+		return diff
+	}
+
+	var visit func(level int, index int)
+	visit = func(level int, index int) {
+		if a[level][index] == b[level][index] {
+			return
+		}
+
+		if level == 0 {
+			diff = append(diff, index)
+			return
+		}
+
+		// Um nó promovido sem irmão possui apenas o filho da esquerda
+		for child := 2 * index; child <= 2*index+1 && child < len(a[level-1]); child++ {
+			visit(level-1, child)
+		}
+	}
+	visit(len(a)-1, 0)
+
+	return diff
+}
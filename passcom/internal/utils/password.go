@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// PasswordParams are the argon2id parameters of a version of the password hashes.
+type PasswordParams struct {
+	Time    uint32
+	Memory  uint32 // Memória em KiB
+	Threads uint8
+	SaltLen int
+	KeyLen  uint32
+}
+
+const (
+	PASSWORD_SCHEME  = "argon2id"
+	PASSWORD_VERSION = 1 // Versão dos parâmetros usada nos novos hashes
+)
+
+// passwordParams holds the parameters of every version of the password hashes. To raise the cost, a new version is
+// added and PASSWORD_VERSION changed; the old versions are kept to verify the hashes not yet upgraded.
+var passwordParams = map[int]PasswordParams{
+	1: {Time: 1, Memory: 64 * 1024, Threads: 4, SaltLen: 16, KeyLen: 32},
+}
+
+// HashPassword hashes a password with argon2id, a random salt and the parameters of the current version.
+// The hash is encoded as "argon2id$<version>$<salt>$<key>", with the salt and the key in base64.
+//
+// Return:
+//   - The encoded hash.
+//   - An error if the salt couldn't be generated.
+func HashPassword(password string) (string, error) {
+	params := passwordParams[PASSWORD_VERSION]
+
+	salt := make([]byte, params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLen)
+	return strings.Join([]string{
+		PASSWORD_SCHEME,
+		strconv.Itoa(PASSWORD_VERSION),
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	}, "$"), nil
+}
+
+// IsPasswordHash reports whether a stored password is a hash made by HashPassword, of any version.
+func IsPasswordHash(stored string) bool {
+	return strings.HasPrefix(stored, PASSWORD_SCHEME+"$")
+}
+
+// VerifyPassword checks a password against a stored one. Passwords stored before the hashes, in plain text,
+// are still accepted, so they can be upgraded on the next login.
+//
+// Return:
+//   - true if the password matches, false otherwise.
+//   - true if the password matches but the stored one is in plain text or uses an older version, and should be
+//     replaced by a new hash.
+func VerifyPassword(stored, password string) (bool, bool) {
+	if !IsPasswordHash(stored) {
+		match := subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+		return match, match
+	}
+
+	version, salt, key, err := decodePasswordHash(stored)
+	if err != nil {
+		return false, false
+	}
+
+	params := passwordParams[version]
+	computed := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+	match := subtle.ConstantTimeCompare(computed, key) == 1
+	return match, match && version != PASSWORD_VERSION
+}
+
+// decodePasswordHash splits a hash made by HashPassword into its version, salt and key.
+func decodePasswordHash(stored string) (int, []byte, []byte, error) {
+	parts := strings.Split(stored, "$")
+	if len(parts) != 4 {
+		return 0, nil, nil, errors.New("malformed password hash")
+	}
+
+	version, err := strconv.Atoi(parts[1])
+	if _, known := passwordParams[version]; err != nil || !known {
+		return 0, nil, nil, errors.New("unknown password hash version " + parts[1])
+	}
+
+	salt, errSalt := base64.RawStdEncoding.DecodeString(parts[2])
+	key, errKey := base64.RawStdEncoding.DecodeString(parts[3])
+	if errSalt != nil || errKey != nil || len(key) == 0 {
+		return 0, nil, nil, errors.New("malformed password hash")
+	}
+	return version, salt, key, nil
+}
@@ -13,8 +13,15 @@ import (
 // 		map[uuid.UUID]*models.Flight | *models.Session
 // }
 
+var dbPath = "database.db"
+
+// SetDbPath changes the SQLite database file opened by OpenDb.
+func SetDbPath(path string) {
+	dbPath = path
+}
+
 func OpenDb() (*gorm.DB, error) {
-	db, err := gorm.Open(sqlite.Open("database.db"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// Algoritmos de assinatura aceitos nos tokens de acesso
+const (
+	TOKEN_HS256 = "HS256"
+	TOKEN_EDDSA = "EdDSA"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenSigner signs and verifies JSON Web Tokens with a single algorithm: HMAC-SHA256 with a shared secret
+// or Ed25519 with a private key, whose public key alone is enough to verify the tokens.
+type TokenSigner struct {
+	algorithm  string
+	secret     []byte
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+}
+
+type tokenHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+// NewTokenSigner creates a signer for the given algorithm and key. The HMAC key is used as given and,
+// when empty, a random key is generated, so the tokens signed are valid only until the process stops.
+// The Ed25519 key is the base64 of the 32 bytes seed or of the 64 bytes private key.
+//
+// Return:
+//   - The signer.
+//   - An error if the algorithm is unknown or the key is not valid for it.
+func NewTokenSigner(algorithm string, key string) (*TokenSigner, error) {
+	switch algorithm {
+	case TOKEN_HS256:
+		secret := []byte(key)
+		if key == "" {
+			secret = make([]byte, 32)
+			if _, err := rand.Read(secret); err != nil {
+				return nil, err
+			}
+		}
+		return &TokenSigner{algorithm: algorithm, secret: secret}, nil
+	case TOKEN_EDDSA:
+		raw, err := base64.StdEncoding.DecodeString(key)
+		if err != nil {
+			return nil, errors.New("Ed25519 key is not base64")
+		}
+		var privateKey ed25519.PrivateKey
+		switch len(raw) {
+		case ed25519.SeedSize:
+			privateKey = ed25519.NewKeyFromSeed(raw)
+		case ed25519.PrivateKeySize:
+			privateKey = ed25519.PrivateKey(raw)
+		default:
+			return nil, errors.New("Ed25519 key must have 32 or 64 bytes")
+		}
+		return &TokenSigner{
+			algorithm:  algorithm,
+			privateKey: privateKey,
+			publicKey:  privateKey.Public().(ed25519.PublicKey),
+		}, nil
+	}
+	return nil, errors.New("unknown token algorithm " + algorithm)
+}
+
+// Sign encodes the claims as the payload of a token signed by the signer.
+func (s *TokenSigner) Sign(claims any) (string, error) {
+	header, err := json.Marshal(tokenHeader{Alg: s.algorithm, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signed := encodeSegment(header) + "." + encodeSegment(payload)
+	return signed + "." + encodeSegment(s.signature([]byte(signed))), nil
+}
+
+// Verify checks the algorithm and the signature of the token and decodes its payload into the claims.
+// The expiration of the claims is not checked.
+//
+// Return:
+//   - ErrInvalidToken if the token is malformed, signed with another algorithm or its signature doesn't match.
+func (s *TokenSigner) Verify(token string, claims any) error {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return ErrInvalidToken
+	}
+
+	header, err := decodeSegment(segments[0])
+	if err != nil {
+		return ErrInvalidToken
+	}
+	var h tokenHeader
+	// O algoritmo vem da configuração, nunca do token, para impedir a troca por "none" ou por outro algoritmo
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != s.algorithm {
+		return ErrInvalidToken
+	}
+
+	signature, err := decodeSegment(segments[2])
+	if err != nil {
+		return ErrInvalidToken
+	}
+	signed := []byte(segments[0] + "." + segments[1])
+	switch s.algorithm {
+	case TOKEN_HS256:
+		if subtle.ConstantTimeCompare(signature, s.signature(signed)) != 1 {
+			return ErrInvalidToken
+		}
+	case TOKEN_EDDSA:
+		if !ed25519.Verify(s.publicKey, signed, signature) {
+			return ErrInvalidToken
+		}
+	}
+
+	payload, err := decodeSegment(segments[1])
+	if err != nil || json.Unmarshal(payload, claims) != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+func (s *TokenSigner) signature(signed []byte) []byte {
+	if s.algorithm == TOKEN_EDDSA {
+		return ed25519.Sign(s.privateKey, signed)
+	}
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(signed)
+	return mac.Sum(nil)
+}
+
+// RandomToken returns a random URL-safe string with the given number of random bytes.
+func RandomToken(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", err
+	}
+	return encodeSegment(buffer), nil
+}
+
+func encodeSegment(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(segment)
+}
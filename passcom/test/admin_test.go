@@ -0,0 +1,90 @@
+package test
+
+import (
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+)
+
+func buySeats(t *testing.T, token string, flight *models.Flight, class string, seats int) {
+	t.Helper()
+	for i := 0; i < seats; i++ {
+		response := server.BuyTicket(models.Request{Auth: token, Data: models.BuyTicket{FlightId: flight.ID, Class: class}})
+		if response.Status != http.StatusOK {
+			t.Fatalf("Expected seat %d to be sold, got %+v", i+1, response)
+		}
+	}
+}
+
+func TestAdminRejectsCapacityBelowSeatsSold(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "admin.capacidade")
+
+	flight := ownFlightBetween(t, 78, 79, 5)
+	buySeats(t, token, flight, "", 3)
+
+	below, zero, exact := 2, 0, 3
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Capacity: &below}); response.Status != http.StatusConflict {
+		t.Errorf("Expected capacity below the seats sold to get %d, got %+v", http.StatusConflict, response)
+	}
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Capacity: &zero}); response.Status != http.StatusBadRequest {
+		t.Errorf("Expected capacity 0 to get %d, got %+v", http.StatusBadRequest, response)
+	}
+
+	unchanged := seatsOf(t, flight)
+	if unchanged.Capacity != 5 || unchanged.Seats != 2 {
+		t.Errorf("Expected refused changes to keep the flight, got capacity %d and %d seats", unchanged.Capacity, unchanged.Seats)
+	}
+
+	// A capacidade pode ser reduzida até os assentos vendidos
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Capacity: &exact}); response.Status != http.StatusOK {
+		t.Fatalf("Expected capacity equal to the seats sold to be accepted, got %+v", response)
+	}
+	if edited := seatsOf(t, flight); edited.Capacity != 3 || edited.Seats != 0 {
+		t.Errorf("Expected capacity 3 without seats left, got capacity %d and %d seats", edited.Capacity, edited.Seats)
+	}
+}
+
+func TestAdminRejectsFareCapacityBelowSeatsSold(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "admin.classes")
+
+	ids := make([]uint, 2)
+	for i, name := range []string{"Admin A", "Admin B"} {
+		dao.GetAirportDAO().Insert(models.Airport{Name: name})
+		ids[i] = dao.GetAirportDAO().FindByName(name).ID
+	}
+
+	economy := models.Fare{Class: models.CabinEconomy, Price: 100, Capacity: 4, RefundPercent: 100}
+	business := models.Fare{Class: models.CabinBusiness, Price: 400, Capacity: 2, RefundPercent: 100}
+	created := system.CreateFlight(models.FlightChange{
+		OriginAirportID:      ids[0],
+		DestinationAirportID: ids[1],
+		Fares:                []models.Fare{economy, business},
+	})
+	if created.Status != http.StatusCreated {
+		t.Fatalf("Expected flight to be created, got %+v", created)
+	}
+	flight, _ := created.Data["Flight"].(*models.Flight)
+	buySeats(t, token, flight, models.CabinBusiness, 2)
+
+	business.Capacity = 1
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Fares: []models.Fare{economy, business}}); response.Status != http.StatusConflict {
+		t.Errorf("Expected class capacity below its seats sold to get %d, got %+v", http.StatusConflict, response)
+	}
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Fares: []models.Fare{economy}}); response.Status != http.StatusConflict {
+		t.Errorf("Expected removing a class with seats sold to get %d, got %+v", http.StatusConflict, response)
+	}
+
+	// A capacidade de uma classe sem vendas pode ser reduzida
+	economy.Capacity, business.Capacity = 1, 2
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Fares: []models.Fare{economy, business}}); response.Status != http.StatusOK {
+		t.Fatalf("Expected fares to be edited, got %+v", response)
+	}
+	edited := seatsOf(t, flight)
+	if edited.Capacity != 3 || edited.Seats != 1 {
+		t.Errorf("Expected capacity 3 with 1 seat left, got capacity %d and %d seats", edited.Capacity, edited.Seats)
+	}
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type repairReply struct {
+	Flights  []models.Flight
+	Repaired int
+}
+
+func sendRepair(from string, company string, flights []models.Flight) repairReply {
+	buckets := make([]int, 0, server.ANTI_ENTROPY_BUCKETS)
+	for i := 0; i < server.ANTI_ENTROPY_BUCKETS; i++ {
+		buckets = append(buckets, i)
+	}
+	msg, _ := models.CreateMessage(from, system.ServerId.String(), map[string]int{from: 1}, map[string]interface{}{
+		"Company": company,
+		"Buckets": buckets,
+		"Flights": flights,
+	})
+	body, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	system.HandleRepair(recorder, httptest.NewRequest(http.MethodPost, "/server/antientropy/repair", bytes.NewReader(body)))
+
+	var response models.Message
+	json.NewDecoder(recorder.Body).Decode(&response)
+	var reply repairReply
+	data, _ := json.Marshal(response.Body)
+	json.Unmarshal(data, &reply)
+	return reply
+}
+
+func TestRepairAppliesCancelledFlight(t *testing.T) {
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	flight := models.Flight{
+		Company:              "peer",
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      90,
+		DestinationAirportID: 91,
+		Seats:                3,
+		Version:              1,
+		VectorClock:          map[string]int{peer.id: 1},
+	}
+	dao.GetFlightDAO().Insert(flight)
+
+	// O dono cancelou o voo, mas o broadcast do cancelamento foi perdido
+	flight.Version = 2
+	flight.VectorClock = map[string]int{peer.id: 2}
+	flight.Status = models.FlightCancelled
+	flight.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	reply := sendRepair(peer.id, "peer", []models.Flight{flight})
+	if reply.Repaired != 1 {
+		t.Errorf("Expected 1 repaired flight, got %d", reply.Repaired)
+	}
+
+	if _, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId); err == nil {
+		t.Errorf("Expected cancelled flight to be removed from the replica")
+	}
+	stored, err := dao.GetFlightDAO().FindByUniqueIdWithDeleted(flight.UniqueId)
+	if err != nil || !stored.DeletedAt.Valid || stored.Version != 2 {
+		t.Fatalf("Expected tombstone with version 2, got %+v (%v)", stored, err)
+	}
+
+	found := false
+	for _, f := range reply.Flights {
+		if f.UniqueId == flight.UniqueId {
+			found = f.DeletedAt.Valid
+		}
+	}
+	if !found {
+		t.Errorf("Expected the tombstone in the flights sent back")
+	}
+
+	// Uma cópia anterior ao cancelamento, vinda de outra réplica, não restaura o voo
+	flight.Version = 1
+	flight.VectorClock = map[string]int{peer.id: 1}
+	flight.Status = models.FlightScheduled
+	flight.DeletedAt = gorm.DeletedAt{}
+	if repaired := sendRepair(uuid.NewString(), "peer", []models.Flight{flight}).Repaired; repaired != 0 {
+		t.Errorf("Expected stale copy to be ignored, got %d repaired", repaired)
+	}
+	if _, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId); err == nil {
+		t.Errorf("Expected cancelled flight not to be restored")
+	}
+}
+
+func TestChangesIncludeCancelledFlights(t *testing.T) {
+	flight := models.Flight{
+		Company:              system.ServerName,
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      90,
+		DestinationAirportID: 91,
+		Version:              2,
+		VectorClock:          map[string]int{system.ServerId.String(): 1000},
+	}
+	flight.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	dao.GetFlightDAO().Insert(flight)
+
+	msg, _ := models.CreateMessage(uuid.NewString(), system.ServerId.String(), map[string]int{}, map[string]int{"Since": 999})
+	body, _ := json.Marshal(msg)
+	recorder := httptest.NewRecorder()
+	system.HandleChanges(recorder, httptest.NewRequest(http.MethodGet, "/server/changes", bytes.NewReader(body)))
+
+	var response models.Message
+	json.NewDecoder(recorder.Body).Decode(&response)
+	var flights []models.Flight
+	data, _ := json.Marshal(response.Body)
+	json.Unmarshal(data, &flights)
+
+	if len(flights) != 1 || flights[0].UniqueId != flight.UniqueId || !flights[0].DeletedAt.Valid {
+		t.Errorf("Expected only the cancelled flight, got %+v", flights)
+	}
+}
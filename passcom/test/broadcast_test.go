@@ -0,0 +1,84 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func sendBroadcast(from string, flight models.Flight) int {
+	msg, _ := models.CreateMessage(from, system.ServerId.String(), map[string]int{from: 1}, flight)
+	body, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	system.HandleBroadcast(recorder, httptest.NewRequest(http.MethodPost, "/server/broadcast", bytes.NewReader(body)))
+	return recorder.Code
+}
+
+func TestBroadcastFromOwnerIsAccepted(t *testing.T) {
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	flight := models.Flight{
+		Company:              "peer",
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      90,
+		DestinationAirportID: 91,
+		Seats:                3,
+		Version:              1,
+	}
+
+	if status := sendBroadcast(peer.id, flight); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	if _, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId); err != nil {
+		t.Errorf("Expected flight broadcast by its owner to be stored: %v", err)
+	}
+}
+
+func TestBroadcastFromOtherServerIsRejected(t *testing.T) {
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	flight := models.Flight{
+		Company:              "peer",
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      90,
+		DestinationAirportID: 91,
+		Seats:                3,
+		Version:              1,
+	}
+	sendBroadcast(peer.id, flight)
+
+	// Outro servidor conectado tenta alterar o voo do par
+	other := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, other)
+	system.Lock.Lock()
+	conn := system.Connections[other.id]
+	conn.Name = "other"
+	system.Connections[other.id] = conn
+	system.Lock.Unlock()
+
+	flight.Seats = 0
+	flight.Version = 2
+	if status := sendBroadcast(other.id, flight); status != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, status)
+	}
+	if status := sendBroadcast(uuid.NewString(), flight); status != http.StatusForbidden {
+		t.Errorf("Expected status %d for unknown sender, got %d", http.StatusForbidden, status)
+	}
+
+	stored, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if err != nil {
+		t.Fatalf("Expected flight to be stored: %v", err)
+	}
+	if stored.Version != 1 || stored.Seats != 3 {
+		t.Errorf("Expected flight unchanged, got version %d with %d seats", stored.Version, stored.Seats)
+	}
+}
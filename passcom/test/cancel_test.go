@@ -0,0 +1,73 @@
+package test
+
+import (
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// ownFlight stores a flight of this server between the test airports and returns it.
+func ownFlight(t *testing.T, seats int) *models.Flight {
+	t.Helper()
+	return ownFlightBetween(t, 90, 91, seats)
+}
+
+func TestCancelOnlyOwnTickets(t *testing.T) {
+	// O cancelamento carimba o voo com o relógio do sistema, compartilhado com os testes do relógio
+	t.Cleanup(resetVectorClock)
+
+	owner := loginAs(t, "dono.ticket")
+	other := loginAs(t, "outro.cliente")
+
+	flight := ownFlight(t, 3)
+	ticket := models.Ticket{ClientId: clientId(t, "dono.ticket"), FlightId: flight.ID, UniqueId: uuid.NewString(), Price: 100}
+	dao.GetTicketDAO().Insert(ticket)
+	stored, _ := dao.GetTicketDAO().FindByUniqueId(ticket.UniqueId)
+
+	if response := server.CancelBuy(stored.ID, models.Request{Auth: other}); response.Status != http.StatusNotFound {
+		t.Errorf("Expected another client to get %d, got %d", http.StatusNotFound, response.Status)
+	}
+
+	// Um agente sem o cabeçalho On-Behalf-Of age como ele mesmo e também não cancela o ticket
+	server.SetRole(models.RoleChange{Username: "outro.cliente", Role: models.RoleAgent})
+	agent := loginAs(t, "outro.cliente")
+	if response := server.CancelBuy(stored.ID, models.Request{Auth: agent}); response.Status != http.StatusNotFound {
+		t.Errorf("Expected agent without On-Behalf-Of to get %d, got %d", http.StatusNotFound, response.Status)
+	}
+
+	if _, err := dao.GetTicketDAO().FindByUniqueId(ticket.UniqueId); err != nil {
+		t.Fatalf("Expected ticket to be kept: %v", err)
+	}
+
+	if response := server.CancelBuy(stored.ID, models.Request{Auth: owner}); response.Status != http.StatusOK {
+		t.Errorf("Expected owner to cancel the ticket, got %+v", response)
+	}
+	if _, err := dao.GetTicketDAO().FindByUniqueId(ticket.UniqueId); err == nil {
+		t.Errorf("Expected ticket to be removed")
+	}
+}
+
+func TestCancelKeepsTicketWhenOwnerFails(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "cancelamento.falho")
+
+	peer := &fakePeer{id: uuid.NewString(), statuses: map[string]int{"/server/ticket/cancel": http.StatusServiceUnavailable}}
+	connectFakePeer(t, peer)
+
+	flight := peerFlightBetween(t, 90, 91, 3)
+	ticket := models.Ticket{ClientId: clientId(t, "cancelamento.falho"), FlightId: flight.ID, UniqueId: uuid.NewString(), Price: 100}
+	dao.GetTicketDAO().Insert(ticket)
+	stored, _ := dao.GetTicketDAO().FindByUniqueId(ticket.UniqueId)
+
+	// O dono do voo não confirma o cancelamento, então o ticket é mantido
+	if response := server.CancelBuy(stored.ID, models.Request{Auth: token}); response.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected failed cancellation to get %d, got %d", http.StatusServiceUnavailable, response.Status)
+	}
+	if _, err := dao.GetTicketDAO().FindByUniqueId(ticket.UniqueId); err != nil {
+		t.Errorf("Expected ticket to be kept: %v", err)
+	}
+}
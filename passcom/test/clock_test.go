@@ -1,7 +1,7 @@
 package test
 
 import (
-	"rumos/internal/server"
+	"passcom/internal/server"
 	"testing"
 
 	"github.com/google/uuid"
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type quotaSale struct {
+	TicketId string
+	Class    string
+	Seat     string
+	Price    uint
+}
+
+type quotaState struct {
+	FlightId string
+	Granted  int
+	Returned int
+	Sold     int
+	Sales    []quotaSale
+}
+
+func decodeStates(body interface{}) []quotaState {
+	var states []quotaState
+	data, _ := json.Marshal(body)
+	json.Unmarshal(data, &states)
+	return states
+}
+
+func stateOf(states []quotaState, flightId string) quotaState {
+	for _, state := range states {
+		if state.FlightId == flightId {
+			return state
+		}
+	}
+	return quotaState{}
+}
+
+func sendQuotaSync(from string, states []quotaState) quotaState {
+	msg, _ := models.CreateMessage(from, system.ServerId.String(), map[string]int{from: 1}, states)
+	body, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	system.HandleQuotaSync(recorder, httptest.NewRequest(http.MethodPost, "/server/quota/sync", bytes.NewReader(body)))
+
+	var response models.Message
+	json.NewDecoder(recorder.Body).Decode(&response)
+	return stateOf(decodeStates(response.Body), states[0].FlightId)
+}
+
+func TestQuotaSalesArePendingUntilConfirmed(t *testing.T) {
+	token := loginAs(t, "custodia.cliente")
+	owner := uuid.NewString()
+
+	flight := models.Flight{
+		Company:              "dono-offline",
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      84,
+		DestinationAirportID: 85,
+		Seats:                5,
+		Capacity:             5,
+		Price:                100,
+	}
+	dao.GetFlightDAO().Insert(flight)
+	stored, _ := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+
+	state := sendQuotaSync(owner, []quotaState{{FlightId: flight.UniqueId, Granted: 2}})
+	if state.Granted != 2 || state.Returned != 0 || state.Sold != 0 {
+		t.Fatalf("Expected 2 seats granted, got %+v", state)
+	}
+
+	// O dono do voo está inacessível, então a venda usa a cota em custódia
+	buy := server.BuyTicket(models.Request{Auth: token, Data: models.BuyTicket{FlightId: stored.ID}})
+	if buy.Status != http.StatusOK {
+		t.Fatalf("Expected sale from the quota, got %+v", buy)
+	}
+
+	client, _ := dao.GetClientDAO().FindById(clientId(t, "custodia.cliente"))
+	if len(client.ClientFlights) != 1 {
+		t.Fatalf("Expected a ticket, got %d", len(client.ClientFlights))
+	}
+	ticket := client.ClientFlights[0]
+	if !ticket.Pending || ticket.Seat != "" || ticket.Price != 100 {
+		t.Errorf("Expected pending ticket without seat at the base price, got %+v", ticket)
+	}
+
+	// Somente os assentos ainda não vendidos são devolvidos
+	state = sendQuotaSync(owner, []quotaState{{FlightId: flight.UniqueId, Granted: 2, Returned: 2}})
+	if state.Granted != 2 || state.Returned != 1 || state.Sold != 1 {
+		t.Errorf("Expected 1 seat returned and 1 sold, got %+v", state)
+	}
+	if len(state.Sales) != 1 || state.Sales[0].TicketId != ticket.UniqueId {
+		t.Errorf("Expected the sale to be reported as pending, got %+v", state.Sales)
+	}
+
+	buy = server.BuyTicket(models.Request{Auth: token, Data: models.BuyTicket{FlightId: stored.ID}})
+	if buy.Status != http.StatusNotAcceptable {
+		t.Errorf("Expected exhausted quota to refuse the sale, got %+v", buy)
+	}
+
+	state = sendQuotaSync(owner, []quotaState{{
+		FlightId: flight.UniqueId,
+		Granted:  2,
+		Returned: 1,
+		Sales:    []quotaSale{{TicketId: ticket.UniqueId, Class: ticket.Class, Seat: "3A", Price: 150}},
+	}})
+	if len(state.Sales) != 0 || state.Sold != 1 {
+		t.Errorf("Expected no pending sales after the confirmation, got %+v", state)
+	}
+
+	confirmed, _ := dao.GetTicketDAO().FindByUniqueId(ticket.UniqueId)
+	if confirmed.Pending || confirmed.Seat != "3A" || confirmed.Price != 150 {
+		t.Errorf("Expected seat and price confirmed by the owner, got %+v", confirmed)
+	}
+}
+
+func TestRebalanceConfirmsPendingSales(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	escrowQuota := system.EscrowQuota
+	t.Cleanup(func() { system.EscrowQuota = escrowQuota })
+	system.EscrowQuota = 2
+
+	flight := ownFlightBetween(t, 86, 87, 10)
+	ticketId := uuid.NewString()
+
+	// O servidor que guarda a cota vendeu um assento e aplica a confirmação recebida na rodada seguinte
+	var confirmation quotaSale
+	peer := &fakePeer{id: uuid.NewString()}
+	peer.replies = map[string]func(models.Message) interface{}{
+		"/server/quota/sync": func(msg models.Message) interface{} {
+			states := decodeStates(msg.Body)
+			for i := range states {
+				if states[i].FlightId != flight.UniqueId {
+					continue
+				}
+				if len(states[i].Sales) > 0 {
+					confirmation = states[i].Sales[0]
+				}
+				states[i].Returned = min(states[i].Returned, states[i].Granted-1)
+				states[i].Sold = 1
+				states[i].Sales = nil
+				if confirmation.TicketId == "" {
+					states[i].Sales = []quotaSale{{TicketId: ticketId, Price: 100}}
+				}
+			}
+			return states
+		},
+	}
+	conn := connectFakePeer(t, peer)
+
+	if _, _, err := system.RebalanceQuotas(peer.id, conn); err != nil {
+		t.Fatalf("Expected quotas to be rebalanced: %v", err)
+	}
+
+	// A rodada da confirmação também repõe o assento vendido
+	quota, _ := dao.GetQuotaDAO().FindByFlightAndHolder(flight.UniqueId, peer.id)
+	if quota.Granted != 3 || quota.Sold != 1 || quota.Returned != 0 {
+		t.Errorf("Expected 3 seats granted and 1 sold, got %+v", quota)
+	}
+
+	sale, err := dao.GetQuotaSaleDAO().FindByTicketId(ticketId)
+	if err != nil {
+		t.Fatalf("Expected the pending sale to be recorded: %v", err)
+	}
+	if sale.Seat == "" || sale.Price == 0 || !sale.Acknowledged {
+		t.Errorf("Expected sale with a seat and a price, applied by the holder, got %+v", sale)
+	}
+	if confirmation.TicketId != ticketId || confirmation.Seat != sale.Seat || confirmation.Price != sale.Price {
+		t.Errorf("Expected confirmation %+v to be sent right away, got %+v", sale, confirmation)
+	}
+	if requests := peer.requests("/server/quota/sync"); requests != 2 {
+		t.Errorf("Expected 2 quota syncs, got %d", requests)
+	}
+
+	updated, _ := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if updated.Seats != 7 || updated.OccupiedSeats() != 1 {
+		t.Errorf("Expected 7 seats left and 1 occupied, got %d and %d", updated.Seats, updated.OccupiedSeats())
+	}
+
+	// Sem cota desejada, os assentos ainda não vendidos voltam ao voo
+	system.EscrowQuota = 0
+	if _, _, err := system.RebalanceQuotas(peer.id, conn); err != nil {
+		t.Fatalf("Expected quotas to be rebalanced: %v", err)
+	}
+
+	quota, _ = dao.GetQuotaDAO().FindByFlightAndHolder(flight.UniqueId, peer.id)
+	if quota.Granted != 3 || quota.Sold != 1 || quota.Returned != 2 {
+		t.Errorf("Expected 2 seats returned, got %+v", quota)
+	}
+	updated, _ = dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if updated.Seats != 9 {
+		t.Errorf("Expected 9 seats left, got %d", updated.Seats)
+	}
+
+	// O cancelamento da venda pendente libera o assento atribuído a ela
+	if status := sendServerCancel(uuid.NewString(), models.SeatRequest{FlightId: flight.UniqueId, TicketId: ticketId}); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	updated, _ = dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if updated.Seats != 10 || updated.OccupiedSeats() != 0 {
+		t.Errorf("Expected 10 seats left and none occupied, got %d and %d", updated.Seats, updated.OccupiedSeats())
+	}
+	if sale, _ := dao.GetQuotaSaleDAO().FindByTicketId(ticketId); !sale.Cancelled {
+		t.Errorf("Expected sale to be cancelled, got %+v", sale)
+	}
+}
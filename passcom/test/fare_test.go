@@ -0,0 +1,66 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"testing"
+)
+
+func TestFlightFareClasses(t *testing.T) {
+	// Sem classes, o voo possui uma única classe econômica com o seu preço e os seus assentos
+	flight := models.Flight{Price: 300, Capacity: 4, Seats: 4}
+	if fare, exists := flight.Fare(""); !exists || fare.Class != models.CabinEconomy || fare.Price != 300 || fare.RefundPercent != 100 {
+		t.Fatalf("unexpected implicit fare %v", fare)
+	}
+	if _, err := flight.TakeSeats(models.CabinBusiness, 1); err == nil {
+		t.Error("unknown fare class should be refused")
+	}
+
+	flight = models.Flight{Capacity: 6, Seats: 6, Fares: []models.Fare{
+		{Class: models.CabinEconomy, Price: 300, Capacity: 4, Seats: 4, RefundPercent: 50},
+		{Class: models.CabinBusiness, Price: 900, Capacity: 2, Seats: 2, RefundPercent: 100},
+	}}
+	if err := models.ValidateFares(flight.Fares); err != nil {
+		t.Fatal(err)
+	}
+
+	if fare, err := flight.TakeSeats(models.CabinBusiness, 2); err != nil || fare.Seats != 0 {
+		t.Fatalf("business seats not taken: %v", err)
+	}
+	// Cada classe possui o seu próprio estoque de assentos
+	if _, err := flight.TakeSeats(models.CabinBusiness, 1); err == nil {
+		t.Error("sold out fare class should be refused")
+	}
+	if fare, _ := flight.Fare(""); fare.Seats != 4 || flight.Seats != 4 {
+		t.Errorf("economy seats changed: %d, flight seats %d", fare.Seats, flight.Seats)
+	}
+
+	flight.ReturnSeats(models.CabinBusiness, 1)
+	if fare, _ := flight.Fare(models.CabinBusiness); fare.Seats != 1 || flight.Seats != 5 {
+		t.Errorf("business seat not returned: %d, flight seats %d", fare.Seats, flight.Seats)
+	}
+
+	if fare, _ := flight.Fare(models.CabinEconomy); fare.Refund(300) != 150 {
+		t.Errorf("expected refund of 150, got %d", fare.Refund(300))
+	}
+
+	if err := models.ValidateFares([]models.Fare{{Class: models.CabinEconomy, Price: 1, Capacity: 1}, {Class: models.CabinEconomy, Price: 1, Capacity: 1}}); err == nil {
+		t.Error("repeated fare class should be refused")
+	}
+}
+
+func TestAssignSeatInFareCabin(t *testing.T) {
+	flight := models.Flight{Capacity: 6, Seats: 6, Layout: models.Layout{
+		{Name: models.CabinBusiness, Rows: 1, Letters: "AC"},
+		{Name: models.CabinEconomy, Rows: 1, Letters: "ABCD"},
+	}}
+
+	if seat, err := flight.AssignSeat("", models.CabinEconomy); err != nil || seat != "2A" {
+		t.Fatalf("expected first economy seat 2A, got %s: %v", seat, err)
+	}
+	if _, err := flight.AssignSeat("1A", models.CabinEconomy); err == nil {
+		t.Error("business seat should be refused to an economy fare")
+	}
+	if seat, err := flight.AssignSeat("1C", models.CabinBusiness); err != nil || seat != "1C" {
+		t.Errorf("business seat 1C not assigned: %v", err)
+	}
+}
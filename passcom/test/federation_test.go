@@ -0,0 +1,141 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/config"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// enableFederation configures the server with the federated login until the end of the test.
+func enableFederation(t *testing.T) {
+	federation := config.Default()
+	federation.DBPath = testDbPath
+	federation.Federation = true
+	server.Configure(federation)
+
+	t.Cleanup(func() {
+		defaults := config.Default()
+		defaults.DBPath = testDbPath
+		server.Configure(defaults)
+	})
+}
+
+// sendFederation sends a federation request to this server as the given sender and returns the status and the body.
+func sendFederation(from string, path string, body interface{}, handler func(http.ResponseWriter, *http.Request)) (int, interface{}) {
+	msg, _ := models.CreateMessage(from, system.ServerId.String(), map[string]int{from: 1}, body)
+	data, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data)))
+
+	var response models.Message
+	json.NewDecoder(recorder.Body).Decode(&response)
+	return recorder.Code, response.Body
+}
+
+func TestFederatedLoginIsVerifiedByHomeCompany(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	enableFederation(t)
+
+	// O servidor de origem aceita somente a senha do cliente
+	peer := &fakePeer{id: uuid.NewString()}
+	peer.replies = map[string]func(models.Message) interface{}{
+		"/server/federation/credentials": func(msg models.Message) interface{} {
+			var check models.CredentialCheck
+			data, _ := json.Marshal(msg.Body)
+			json.Unmarshal(data, &check)
+			if check.Username != "viajante" || check.Password != "senhaDaOrigem" {
+				return nil
+			}
+			return models.FederatedClient{Username: check.Username, Name: "Viajante"}
+		},
+	}
+	connectFakePeer(t, peer)
+
+	if response := server.Login(models.LoginCredentials{Username: "viajante@peer", Password: "errada"}); response.Status != http.StatusUnauthorized {
+		t.Errorf("Expected wrong password to get %d, got %+v", http.StatusUnauthorized, response)
+	}
+
+	// Sem a companhia de origem, a senha não é enviada a nenhum servidor
+	if response := server.Login(models.LoginCredentials{Username: "viajante", Password: "senhaDaOrigem"}); response.Status != http.StatusUnauthorized {
+		t.Errorf("Expected username without company to get %d, got %+v", http.StatusUnauthorized, response)
+	}
+	if checks := peer.requests("/server/federation/credentials"); checks != 1 {
+		t.Errorf("Expected only the explicit login to be verified by peer, got %d checks", checks)
+	}
+
+	response := server.Login(models.LoginCredentials{Username: "viajante@peer", Password: "senhaDaOrigem"})
+	token, _ := response.Data["token"].(string)
+	if response.Status != http.StatusOK || token == "" || response.Data["home"] != "peer" {
+		t.Fatalf("Expected federated login scoped to peer, got %+v", response)
+	}
+
+	client, err := dao.GetClientDAO().FindByUsername("viajante@peer")
+	if err != nil || client.HomeCompany != "peer" || client.Name != "Viajante" {
+		t.Fatalf("Expected federated client of peer, got %+v (%v)", client, err)
+	}
+
+	// O ticket comprado aqui pertence ao cliente federado e é listado somente para a companhia de origem
+	flight := ownFlightBetween(t, 88, 89, 3)
+	buySeats(t, token, flight, "", 1)
+
+	query := models.FederatedTicketQuery{Username: "viajante", Home: "peer"}
+	status, body := sendFederation(peer.id, "/server/federation/tickets", query, system.HandleFederatedTickets)
+	tickets, _ := body.([]interface{})
+	if status != http.StatusOK || len(tickets) != 1 {
+		t.Errorf("Expected the ticket to be listed to the home company, got %d with %v", status, body)
+	}
+
+	if status, _ := sendFederation(uuid.NewString(), "/server/federation/tickets", query, system.HandleFederatedTickets); status != http.StatusForbidden {
+		t.Errorf("Expected another server to get %d, got %d", http.StatusForbidden, status)
+	}
+}
+
+func TestTicketsIncludeFederatedTickets(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	enableFederation(t)
+	token := loginAs(t, "residente")
+
+	peer := &fakePeer{id: uuid.NewString()}
+	peer.replies = map[string]func(models.Message) interface{}{
+		"/server/federation/tickets": func(msg models.Message) interface{} {
+			return []map[string]interface{}{{"ID": 7, "UniqueId": "ticket-remoto", "Company": "peer"}}
+		},
+	}
+	connectFakePeer(t, peer)
+
+	flight := ownFlightBetween(t, 88, 89, 3)
+	buySeats(t, token, flight, "", 1)
+
+	response := server.GetTickets(models.Request{Auth: token})
+	tickets, _ := response.Data["Tickets"].([]map[string]interface{})
+	if response.Status != http.StatusOK || len(tickets) != 2 {
+		t.Fatalf("Expected local and federated tickets, got %+v", response)
+	}
+
+	remote := tickets[1]
+	if remote["UniqueId"] != "ticket-remoto" || remote["Server"] != "peer" {
+		t.Errorf("Expected the ticket bought on peer, got %v", remote)
+	}
+	if _, exists := remote["ID"]; exists {
+		t.Errorf("Expected the local ID of the other server to be removed, got %v", remote["ID"])
+	}
+
+	// A companhia de origem verifica as credenciais dos seus clientes para os outros servidores
+	check := models.CredentialCheck{Username: "residente", Password: "senha"}
+	if status, _ := sendFederation(peer.id, "/server/federation/credentials", check, system.HandleVerifyCredentials); status != http.StatusOK {
+		t.Errorf("Expected valid credentials to get %d, got %d", http.StatusOK, status)
+	}
+	check.Password = "errada"
+	if status, _ := sendFederation(peer.id, "/server/federation/credentials", check, system.HandleVerifyCredentials); status != http.StatusUnauthorized {
+		t.Errorf("Expected wrong password to get %d, got %d", http.StatusUnauthorized, status)
+	}
+}
@@ -0,0 +1,37 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"testing"
+	"time"
+)
+
+func TestFlightChangeStatus(t *testing.T) {
+	flight := models.Flight{Status: models.FlightScheduled}
+
+	if err := flight.ChangeStatus(models.FlightDelayed, nil); err == nil {
+		t.Error("delayed flight without estimated departure should be refused")
+	}
+
+	estimated := time.Date(2026, 12, 2, 22, 0, 0, 0, time.UTC)
+	if err := flight.ChangeStatus(models.FlightDelayed, &estimated); err != nil || !flight.EstimatedDeparture.Equal(estimated) {
+		t.Fatalf("delay not applied: %v", err)
+	}
+
+	for _, status := range []string{models.FlightBoarding, models.FlightDeparted} {
+		if err := flight.ChangeStatus(status, nil); err != nil {
+			t.Fatalf("%s not applied: %v", status, err)
+		}
+	}
+
+	// Depois da partida, o voo só pode chegar
+	if err := flight.ChangeStatus(models.FlightBoarding, nil); err == nil {
+		t.Error("departed flight should not go back to boarding")
+	}
+	if err := flight.ChangeStatus(models.FlightArrived, nil); err != nil {
+		t.Fatalf("arrival not applied: %v", err)
+	}
+	if err := flight.ChangeStatus("landed", nil); err == nil {
+		t.Error("unknown status should be refused")
+	}
+}
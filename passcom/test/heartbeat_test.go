@@ -0,0 +1,49 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHeartbeatWithoutChangesDoesNotCatchUp(t *testing.T) {
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	for i := 0; i < 5; i++ {
+		if !system.SendHeartbeat(peer.id, currentConnection(peer.id)) {
+			t.Fatalf("Expected peer to be online")
+		}
+	}
+
+	if peer.pulls != 0 {
+		t.Errorf("Expected no changes request on plain heartbeats, got %d", peer.pulls)
+	}
+}
+
+func TestHeartbeatWithChangesCatchesUpOnce(t *testing.T) {
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	peer.changes = 3
+	peer.flights = []models.Flight{{
+		Company:              "peer",
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      90,
+		DestinationAirportID: 91,
+		Version:              1,
+		VectorClock:          map[string]int{peer.id: 3},
+	}}
+
+	for i := 0; i < 5; i++ {
+		system.SendHeartbeat(peer.id, currentConnection(peer.id))
+	}
+
+	if peer.pulls != 1 {
+		t.Errorf("Expected a single changes request, got %d", peer.pulls)
+	}
+	if synced := currentConnection(peer.id).SyncedClock; synced != 3 {
+		t.Errorf("Expected synced clock 3, got %d", synced)
+	}
+}
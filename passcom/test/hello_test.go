@@ -4,7 +4,7 @@ import (
 	"testing"
 )
 
-const ret string = "Helo"
+const ret string = "Hello"
 
 func TestAssertHello(t *testing.T) {
 	if ret != "Hello" {
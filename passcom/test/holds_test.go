@@ -0,0 +1,160 @@
+package test
+
+import (
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"sync"
+	"testing"
+	"time"
+)
+
+func holdSeats(t *testing.T, token string, flight *models.Flight, seats int) models.Hold {
+	t.Helper()
+	response := server.HoldSeats(models.Request{Auth: token, Data: models.HoldRequest{FlightId: flight.ID, Seats: seats}})
+	hold, _ := response.Data["hold"].(models.Hold)
+	if response.Status != http.StatusOK || hold.HoldId == "" {
+		t.Fatalf("Expected seats to be held, got %+v", response)
+	}
+	return hold
+}
+
+func seatsOf(t *testing.T, flight *models.Flight) *models.Flight {
+	t.Helper()
+	stored, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	if err != nil {
+		t.Fatalf("Expected flight %s: %v", flight.UniqueId, err)
+	}
+	return stored
+}
+
+func editPrice(t *testing.T, flight *models.Flight, price uint) {
+	t.Helper()
+	if response := system.EditFlight(flight.UniqueId, models.FlightChange{Price: &price}); response.Status != http.StatusOK {
+		t.Fatalf("Expected flight to be edited, got %+v", response)
+	}
+}
+
+func TestHoldCheckoutCreatesTicketsAtHeldPrice(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "reserva.checkout")
+	other := loginAs(t, "reserva.outro")
+
+	flight := ownFlightBetween(t, 76, 77, 5)
+	hold := holdSeats(t, token, flight, 2)
+	if stored := seatsOf(t, flight); stored.Seats != 3 {
+		t.Errorf("Expected held seats to be taken from the flight, got %d seats", stored.Seats)
+	}
+
+	// O preço da reserva fica travado mesmo que o voo mude antes do checkout
+	editPrice(t, flight, 500)
+
+	if response := server.CheckoutHold(models.Request{Auth: other, Data: models.Checkout{HoldId: hold.HoldId}}); response.Status != http.StatusNotFound {
+		t.Errorf("Expected another client to get %d, got %d", http.StatusNotFound, response.Status)
+	}
+	if response := server.CheckoutHold(models.Request{Auth: token, Data: models.Checkout{HoldId: hold.HoldId}}); response.Status != http.StatusOK {
+		t.Fatalf("Expected checkout to succeed, got %+v", response)
+	}
+
+	tickets := ticketsOf(t, "reserva.checkout")
+	if len(tickets) != 2 {
+		t.Fatalf("Expected a ticket per held seat, got %d", len(tickets))
+	}
+	if tickets[0].Price != hold.Price || tickets[1].Price != hold.Price {
+		t.Errorf("Expected tickets at the held price %d, got %d and %d", hold.Price, tickets[0].Price, tickets[1].Price)
+	}
+	if tickets[0].Seat == "" || tickets[0].Seat == tickets[1].Seat {
+		t.Errorf("Expected distinct assigned seats, got %q and %q", tickets[0].Seat, tickets[1].Seat)
+	}
+
+	stored := seatsOf(t, flight)
+	if stored.Seats != 3 || stored.OccupiedSeats() != 2 {
+		t.Errorf("Expected 3 seats left and 2 occupied, got %d and %d", stored.Seats, stored.OccupiedSeats())
+	}
+	if checked, _ := dao.GetHoldDAO().FindByHoldId(hold.HoldId); checked.Status != models.COMMITED {
+		t.Errorf("Expected hold to be checked out, got %v", checked.Status)
+	}
+
+	// Um checkout repetido não cria novos tickets
+	if response := server.CheckoutHold(models.Request{Auth: token, Data: models.Checkout{HoldId: hold.HoldId}}); response.Status != http.StatusConflict {
+		t.Errorf("Expected repeated checkout to get %d, got %d", http.StatusConflict, response.Status)
+	}
+	if tickets := ticketsOf(t, "reserva.checkout"); len(tickets) != 2 {
+		t.Errorf("Expected no new tickets, got %d", len(tickets))
+	}
+}
+
+func TestConcurrentHoldCheckoutsCreateTicketsOnce(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "reserva.concorrente")
+
+	flight := ownFlightBetween(t, 76, 77, 5)
+	hold := holdSeats(t, token, flight, 2)
+
+	statuses := make([]int, 5)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = server.CheckoutHold(models.Request{Auth: token, Data: models.Checkout{HoldId: hold.HoldId}}).Status
+		}(i)
+	}
+	wg.Wait()
+
+	// Somente um checkout cria os tickets; os demais são recusados
+	succeeded := 0
+	for _, status := range statuses {
+		switch status {
+		case http.StatusOK:
+			succeeded++
+		case http.StatusConflict:
+		default:
+			t.Errorf("Expected %d or %d, got %d", http.StatusOK, http.StatusConflict, status)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("Expected one checkout to succeed, got %d", succeeded)
+	}
+	if tickets := ticketsOf(t, "reserva.concorrente"); len(tickets) != 2 {
+		t.Errorf("Expected a ticket per held seat, got %d", len(tickets))
+	}
+	if stored := seatsOf(t, flight); stored.OccupiedSeats() != 2 {
+		t.Errorf("Expected 2 occupied seats, got %d", stored.OccupiedSeats())
+	}
+}
+
+func TestExpiredHoldReleasesSeats(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "reserva.expirada")
+
+	flight := ownFlightBetween(t, 76, 77, 5)
+	hold := holdSeats(t, token, flight, 2)
+
+	stored, _ := dao.GetHoldDAO().FindByHoldId(hold.HoldId)
+	stored.ExpiresAt = time.Now().Add(-time.Second)
+	dao.GetHoldDAO().Update(*stored)
+
+	if response := server.CheckoutHold(models.Request{Auth: token, Data: models.Checkout{HoldId: hold.HoldId}}); response.Status != http.StatusConflict {
+		t.Errorf("Expected expired hold checkout to get %d, got %d", http.StatusConflict, response.Status)
+	}
+
+	system.ReleaseExpiredHolds()
+
+	if released, _ := dao.GetHoldDAO().FindByHoldId(hold.HoldId); released.Status != models.REJECTED {
+		t.Errorf("Expected hold to be released, got %v", released.Status)
+	}
+	if stored := seatsOf(t, flight); stored.Seats != 5 {
+		t.Errorf("Expected held seats to return to the flight, got %d seats", stored.Seats)
+	}
+	if tickets := ticketsOf(t, "reserva.expirada"); len(tickets) != 0 {
+		t.Errorf("Expected no tickets, got %d", len(tickets))
+	}
+
+	// Uma nova varredura não devolve os assentos novamente
+	system.ReleaseExpiredHolds()
+	if stored := seatsOf(t, flight); stored.Seats != 5 {
+		t.Errorf("Expected seats to be released once, got %d seats", stored.Seats)
+	}
+}
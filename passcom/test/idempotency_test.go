@@ -0,0 +1,99 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFinalStatus(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusCreated, http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound} {
+		if !models.FinalStatus(status) {
+			t.Errorf("Expected %d to be stored", status)
+		}
+	}
+
+	for _, status := range []int{http.StatusUnauthorized, http.StatusNotAcceptable, http.StatusRequestTimeout, http.StatusConflict,
+		http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		if models.FinalStatus(status) {
+			t.Errorf("Expected %d not to be stored", status)
+		}
+	}
+}
+
+func sendServerCancel(id string, request models.SeatRequest) int {
+	msg, _ := models.CreateMessage(uuid.NewString(), system.ServerId.String(), map[string]int{}, request)
+	msg.Id = id
+	body, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	system.HandleServerTicketCancel(recorder, httptest.NewRequest(http.MethodDelete, "/server/ticket/cancel", bytes.NewReader(body)))
+	return recorder.Code
+}
+
+func TestServerCancelIsIdempotent(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+
+	flight := ownFlight(t, 3)
+	id := uuid.NewString()
+	request := models.SeatRequest{FlightId: flight.UniqueId}
+
+	if status := sendServerCancel(id, request); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+	cancelled, _ := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+
+	// A mesma mensagem reenviada recebe a resposta armazenada, sem alterar o voo novamente
+	if status := sendServerCancel(id, request); status != http.StatusOK {
+		t.Errorf("Expected replayed status %d, got %d", http.StatusOK, status)
+	}
+
+	// O mesmo ID com outros dados é recusado
+	other := ownFlight(t, 3)
+	if status := sendServerCancel(id, models.SeatRequest{FlightId: other.UniqueId}); status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status %d for a reused ID, got %d", http.StatusUnprocessableEntity, status)
+	}
+
+	if stored, _ := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId); stored.Version != cancelled.Version {
+		t.Errorf("Expected version %d after retries, got %d", cancelled.Version, stored.Version)
+	}
+	if stored, _ := dao.GetFlightDAO().FindByUniqueId(other.UniqueId); stored.Version != other.Version {
+		t.Errorf("Expected other flight unchanged, got version %d", stored.Version)
+	}
+}
+
+func TestConcurrentServerCancelsRunOnce(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+
+	flight := ownFlight(t, 3)
+	id := uuid.NewString()
+	request := models.SeatRequest{FlightId: flight.UniqueId}
+
+	statuses := make([]int, 8)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = sendServerCancel(id, request)
+		}(i)
+	}
+	wg.Wait()
+
+	// Todas as cópias recebem a resposta da primeira execução, que altera o voo uma única vez
+	for _, status := range statuses {
+		if status != http.StatusOK {
+			t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+		}
+	}
+	if stored, _ := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId); stored.Version != flight.Version+1 {
+		t.Errorf("Expected version %d, got %d", flight.Version+1, stored.Version)
+	}
+}
@@ -0,0 +1,226 @@
+package test
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// fakePeer answers the messages of this server like a connected company whose clock advances on every request,
+// counting the requests by path and the requests for its flight changes.
+type fakePeer struct {
+	id      string
+	clock   int
+	changes int // Maior valor do relógio gravado em um voo do par
+	flights []models.Flight
+	pulls   int
+
+	mu       sync.Mutex
+	received map[string]int                              // Requisições recebidas por caminho
+	replies  map[string]func(models.Message) interface{} // Corpo da resposta por caminho, a partir da mensagem recebida
+	statuses map[string]int                              // Status da resposta por caminho (200 por padrão)
+}
+
+func (p *fakePeer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	if p.received == nil {
+		p.received = make(map[string]int)
+	}
+	p.received[r.URL.Path]++
+	p.clock++
+	p.mu.Unlock()
+
+	var body interface{}
+	switch r.URL.Path {
+	case "/server/heartbeat":
+		body = models.Heartbeat{Status: "Healthy", Changes: p.changes}
+	case "/server/changes":
+		p.pulls++
+		body = p.flights
+	default:
+		if reply, exists := p.replies[r.URL.Path]; exists {
+			var msg models.Message
+			json.NewDecoder(r.Body).Decode(&msg)
+			body = reply(msg)
+		}
+	}
+
+	msg, _ := models.CreateMessage(p.id, system.ServerId.String(), map[string]int{p.id: p.clock}, body)
+	if status, exists := p.statuses[r.URL.Path]; exists {
+		w.WriteHeader(status)
+	}
+	json.NewEncoder(w).Encode(msg)
+}
+
+// requests returns how many requests the peer received on the given path.
+func (p *fakePeer) requests(path string) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.received[path]
+}
+
+// connectFakePeer serves the peer over HTTP and adds it to the connections as the online company "peer".
+func connectFakePeer(t *testing.T, peer *fakePeer) models.Connection {
+	server := httptest.NewServer(peer)
+	t.Cleanup(server.Close)
+
+	host, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	conn := models.Connection{Name: "peer", Address: host, Port: port, IsOnline: true}
+
+	system.Lock.Lock()
+	system.Connections[peer.id] = conn
+	system.Lock.Unlock()
+	t.Cleanup(func() {
+		system.Lock.Lock()
+		delete(system.Connections, peer.id)
+		system.Lock.Unlock()
+	})
+	return conn
+}
+
+// loginAs stores a client with the given username and returns the token of its session.
+func loginAs(t *testing.T, username string) string {
+	t.Helper()
+	dao.GetClientDAO().Insert(models.Client{Name: "Cliente Teste", Username: username, Password: "senha"})
+
+	response := server.Login(models.LoginCredentials{Username: username, Password: "senha"})
+	token, _ := response.Data["token"].(string)
+	if response.Status != http.StatusOK || token == "" {
+		t.Fatalf("Expected %s to log in, got %+v", username, response)
+	}
+	return token
+}
+
+func clientId(t *testing.T, username string) uint {
+	t.Helper()
+	client, err := dao.GetClientDAO().FindByUsername(username)
+	if err != nil {
+		t.Fatalf("Expected client %s: %v", username, err)
+	}
+	return client.ID
+}
+
+func ticketsOf(t *testing.T, username string) []models.Ticket {
+	t.Helper()
+	client, err := dao.GetClientDAO().FindById(clientId(t, username))
+	if err != nil {
+		t.Fatalf("Expected client %s: %v", username, err)
+	}
+	return client.ClientFlights
+}
+
+// ownFlightBetween stores a flight of this server between the given airports and returns it.
+func ownFlightBetween(t *testing.T, origin uint, destination uint, seats int) *models.Flight {
+	t.Helper()
+	return flightBetween(t, system.ServerName, origin, destination, seats)
+}
+
+// peerFlightBetween stores a replica of a flight of the fake peer between the given airports and returns it.
+func peerFlightBetween(t *testing.T, origin uint, destination uint, seats int) *models.Flight {
+	t.Helper()
+	return flightBetween(t, "peer", origin, destination, seats)
+}
+
+func flightBetween(t *testing.T, company string, origin uint, destination uint, seats int) *models.Flight {
+	t.Helper()
+	uniqueId := uuid.NewString()
+	dao.GetFlightDAO().Insert(models.Flight{
+		Company:              company,
+		UniqueId:             uniqueId,
+		OriginAirportID:      origin,
+		DestinationAirportID: destination,
+		Seats:                seats,
+		Capacity:             seats,
+		Price:                100,
+	})
+
+	flight, err := dao.GetFlightDAO().FindByUniqueId(uniqueId)
+	if err != nil {
+		t.Fatalf("Expected flight to be stored: %v", err)
+	}
+	return flight
+}
+
+func TestItineraryCommitsEveryLeg(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "itinerario.completo")
+
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	first := ownFlightBetween(t, 70, 71, 3)
+	second := peerFlightBetween(t, 71, 72, 3)
+
+	response := server.BuyItinerary(models.Request{Auth: token, Data: models.BuyItinerary{FlightIds: []uint{first.ID, second.ID}}})
+	if response.Status != http.StatusOK {
+		t.Fatalf("Expected itinerary to be bought, got %+v", response)
+	}
+
+	if tickets := ticketsOf(t, "itinerario.completo"); len(tickets) != 2 {
+		t.Errorf("Expected a ticket per leg, got %d", len(tickets))
+	}
+	if stored, _ := dao.GetFlightDAO().FindByUniqueId(first.UniqueId); stored.Seats != 2 {
+		t.Errorf("Expected local leg to take a seat, got %d seats", stored.Seats)
+	}
+	if prepares := peer.requests("/server/ticket/prepare"); prepares != 1 {
+		t.Errorf("Expected the peer leg to be prepared once, got %d", prepares)
+	}
+	if commits := peer.requests("/server/ticket/commit"); commits != 1 {
+		t.Errorf("Expected the commit decision to be sent once, got %d", commits)
+	}
+}
+
+func TestItineraryAbortsEveryLegWhenOneFails(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "itinerario.falho")
+
+	// O dono do segundo trecho vota "não"
+	peer := &fakePeer{id: uuid.NewString(), statuses: map[string]int{"/server/ticket/prepare": http.StatusConflict}}
+	connectFakePeer(t, peer)
+
+	first := ownFlightBetween(t, 70, 71, 3)
+	second := peerFlightBetween(t, 71, 72, 3)
+
+	response := server.BuyItinerary(models.Request{Auth: token, Data: models.BuyItinerary{FlightIds: []uint{first.ID, second.ID}}})
+	if response.Status != http.StatusNotAcceptable {
+		t.Fatalf("Expected itinerary to be refused, got %+v", response)
+	}
+
+	if tickets := ticketsOf(t, "itinerario.falho"); len(tickets) != 0 {
+		t.Errorf("Expected no tickets, got %d", len(tickets))
+	}
+
+	// O assento reservado no primeiro trecho é devolvido
+	if stored, _ := dao.GetFlightDAO().FindByUniqueId(first.UniqueId); stored.Seats != 3 {
+		t.Errorf("Expected local leg to be released, got %d seats", stored.Seats)
+	}
+	if aborts := peer.requests("/server/ticket/abort"); aborts != 1 {
+		t.Errorf("Expected the abort decision to be sent once, got %d", aborts)
+	}
+	if commits := peer.requests("/server/ticket/commit"); commits != 0 {
+		t.Errorf("Expected no commit decision, got %d", commits)
+	}
+}
+
+func TestItineraryRejectsDisconnectedLegs(t *testing.T) {
+	token := loginAs(t, "itinerario.desconexo")
+
+	first := ownFlightBetween(t, 70, 71, 3)
+	second := ownFlightBetween(t, 72, 73, 3)
+
+	response := server.BuyItinerary(models.Request{Auth: token, Data: models.BuyItinerary{FlightIds: []uint{first.ID, second.ID}}})
+	if response.Status != http.StatusBadRequest {
+		t.Errorf("Expected disconnected legs to be refused, got %+v", response)
+	}
+	if stored, _ := dao.GetFlightDAO().FindByUniqueId(first.UniqueId); stored.Seats != 3 {
+		t.Errorf("Expected no seat taken, got %d seats", stored.Seats)
+	}
+}
@@ -0,0 +1,53 @@
+package test
+
+import (
+	"passcom/internal/utils"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func merkleLeaves(n int) []string {
+	leaves := make([]string, n)
+	for i := range leaves {
+		leaves[i] = utils.HashString(strconv.Itoa(i))
+	}
+	return leaves
+}
+
+func TestMerkleEqualTrees(t *testing.T) {
+	a := utils.MerkleTree(merkleLeaves(16))
+	b := utils.MerkleTree(merkleLeaves(16))
+
+	if diff := utils.MerkleDiff(a, b); len(diff) != 0 {
+		t.Errorf("Expected no differences, got %v", diff)
+	}
+}
+
+func TestMerkleDifferentLeaves(t *testing.T) {
+	changed := merkleLeaves(16)
+	changed[3] = utils.HashString("changed")
+	changed[12] = utils.HashString("changed")
+
+	diff := utils.MerkleDiff(utils.MerkleTree(merkleLeaves(16)), utils.MerkleTree(changed))
+	if !reflect.DeepEqual(diff, []int{3, 12}) {
+		t.Errorf("Expected [3 12], got %v", diff)
+	}
+}
+
+func TestMerkleOddLeaves(t *testing.T) {
+	changed := merkleLeaves(5)
+	changed[4] = utils.HashString("changed")
+
+	diff := utils.MerkleDiff(utils.MerkleTree(merkleLeaves(5)), utils.MerkleTree(changed))
+	if !reflect.DeepEqual(diff, []int{4}) {
+		t.Errorf("Expected [4], got %v", diff)
+	}
+}
+
+func TestMerkleMissingTree(t *testing.T) {
+	diff := utils.MerkleDiff(nil, utils.MerkleTree(merkleLeaves(4)))
+	if !reflect.DeepEqual(diff, []int{0, 1, 2, 3}) {
+		t.Errorf("Expected [0 1 2 3], got %v", diff)
+	}
+}
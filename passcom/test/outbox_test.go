@@ -0,0 +1,149 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func setOnline(id string, online bool) {
+	system.Lock.Lock()
+	conn := system.Connections[id]
+	conn.IsOnline = online
+	system.Connections[id] = conn
+	system.Lock.Unlock()
+}
+
+func currentConnection(id string) models.Connection {
+	system.Lock.RLock()
+	defer system.Lock.RUnlock()
+	return system.Connections[id]
+}
+
+// reconnect sends a heartbeat to a connection seen as offline, which redelivers its outbox.
+func reconnect(id string) {
+	conn := currentConnection(id)
+	conn.IsOnline = false
+	system.SendHeartbeat(id, conn)
+}
+
+// buySeat buys a seat of a flight of this server, which broadcasts its new state.
+func buySeat(t *testing.T, token string, flight *models.Flight) {
+	t.Helper()
+	if response := server.BuyTicket(models.Request{Auth: token, Data: models.BuyTicket{FlightId: flight.ID}}); response.Status != http.StatusOK {
+		t.Fatalf("Expected seat to be bought, got %+v", response)
+	}
+}
+
+func queued(t *testing.T, peer string, flight *models.Flight) []models.OutboxMessage {
+	t.Helper()
+	entries, err := dao.GetOutboxDAO().FindByPeerAndFlight(peer, flight.UniqueId)
+	if err != nil {
+		t.Fatalf("Expected outbox of %s: %v", peer, err)
+	}
+	return entries
+}
+
+func TestOutboxSupersedesQueuedBroadcasts(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "outbox.substituido")
+
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+	setOnline(peer.id, false)
+
+	flight := ownFlightBetween(t, 74, 75, 5)
+	buySeat(t, token, flight)
+	buySeat(t, token, flight)
+
+	// Somente o estado mais recente do voo fica na fila da conexão offline
+	entries := queued(t, peer.id, flight)
+	if len(entries) != 1 {
+		t.Fatalf("Expected a single queued broadcast, got %d", len(entries))
+	}
+	var queuedFlight models.Flight
+	json.Unmarshal([]byte(entries[0].Flight), &queuedFlight)
+	if queuedFlight.Seats != 3 {
+		t.Errorf("Expected the latest state to be queued, got %d seats", queuedFlight.Seats)
+	}
+	if broadcasts := peer.requests("/server/broadcast"); broadcasts != 0 {
+		t.Errorf("Expected no broadcast to an offline connection, got %d", broadcasts)
+	}
+
+	reconnect(peer.id)
+
+	if broadcasts := peer.requests("/server/broadcast"); broadcasts != 1 {
+		t.Errorf("Expected the queued broadcast to be delivered once, got %d", broadcasts)
+	}
+	if entries := queued(t, peer.id, flight); len(entries) != 0 {
+		t.Errorf("Expected empty outbox after the delivery, got %d", len(entries))
+	}
+}
+
+func TestOutboxBacksOffFailedDeliveries(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "outbox.falha")
+
+	peer := &fakePeer{id: uuid.NewString(), statuses: map[string]int{"/server/broadcast": http.StatusServiceUnavailable}}
+	connectFakePeer(t, peer)
+
+	flight := ownFlightBetween(t, 74, 75, 5)
+	expectBackoff := func(attempts int, backoff time.Duration) models.OutboxMessage {
+		t.Helper()
+		entries := queued(t, peer.id, flight)
+		if len(entries) != 1 {
+			t.Fatalf("Expected the failed broadcast to stay queued, got %d", len(entries))
+		}
+		entry := entries[0]
+		if delay := time.Until(entry.NextAttempt); entry.Attempts != attempts || delay > backoff || delay < backoff-time.Second {
+			t.Errorf("Expected attempt %d retried in %v, got attempt %d in %v", attempts, backoff, entry.Attempts, delay)
+		}
+		return entry
+	}
+
+	buySeat(t, token, flight)
+	expectBackoff(1, server.OUTBOX_BASE_BACKOFF)
+
+	// O intervalo dobra a cada falha, até o máximo
+	reconnect(peer.id)
+	entry := expectBackoff(2, 2*server.OUTBOX_BASE_BACKOFF)
+
+	entry.Attempts = 20
+	dao.GetOutboxDAO().Update(entry)
+	reconnect(peer.id)
+	expectBackoff(21, server.OUTBOX_MAX_BACKOFF)
+
+	delete(peer.statuses, "/server/broadcast")
+	reconnect(peer.id)
+	if entries := queued(t, peer.id, flight); len(entries) != 0 {
+		t.Errorf("Expected empty outbox after the delivery, got %d", len(entries))
+	}
+	if broadcasts := peer.requests("/server/broadcast"); broadcasts != 4 {
+		t.Errorf("Expected 4 delivery attempts, got %d", broadcasts)
+	}
+}
+
+func TestOutboxDropsRefusedBroadcasts(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "outbox.recusado")
+
+	peer := &fakePeer{id: uuid.NewString(), statuses: map[string]int{"/server/broadcast": http.StatusForbidden}}
+	connectFakePeer(t, peer)
+
+	// Um broadcast recusado com um erro do cliente não é aceito em outra tentativa
+	flight := ownFlightBetween(t, 74, 75, 5)
+	buySeat(t, token, flight)
+
+	if entries := queued(t, peer.id, flight); len(entries) != 0 {
+		t.Errorf("Expected the refused broadcast to be dropped, got %d queued", len(entries))
+	}
+	if broadcasts := peer.requests("/server/broadcast"); broadcasts != 1 {
+		t.Errorf("Expected a single delivery attempt, got %d", broadcasts)
+	}
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"passcom/internal/utils"
+	"strings"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	hash, err := utils.HashPassword("senhaSegura123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utils.IsPasswordHash(hash) || strings.Contains(hash, "senhaSegura123") {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+
+	if match, upgrade := utils.VerifyPassword(hash, "senhaSegura123"); !match || upgrade {
+		t.Errorf("expected match without upgrade, got %v %v", match, upgrade)
+	}
+	if match, _ := utils.VerifyPassword(hash, "senhaErrada"); match {
+		t.Error("wrong password should not match")
+	}
+
+	// O sal é aleatório, então a mesma senha gera hashes diferentes
+	if other, _ := utils.HashPassword("senhaSegura123"); other == hash {
+		t.Error("hashes of the same password should differ")
+	}
+}
+
+func TestVerifyPlaintextPassword(t *testing.T) {
+	// Senhas ainda em texto puro são aceitas e marcadas para receber um hash
+	if match, upgrade := utils.VerifyPassword("senhaSegura123", "senhaSegura123"); !match || !upgrade {
+		t.Errorf("expected match with upgrade, got %v %v", match, upgrade)
+	}
+	if match, upgrade := utils.VerifyPassword("senhaSegura123", "senha"); match || upgrade {
+		t.Errorf("wrong password should not match, got %v %v", match, upgrade)
+	}
+	if match, _ := utils.VerifyPassword("argon2id$9$c2FsdA$a2V5", "senha"); match {
+		t.Error("hash of unknown version should not match")
+	}
+}
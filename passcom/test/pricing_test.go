@@ -0,0 +1,43 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"testing"
+	"time"
+)
+
+func TestLoadFactorPricing(t *testing.T) {
+	pricing := models.DefaultLoadFactorPricing()
+	now := time.Date(2026, 12, 1, 12, 0, 0, 0, time.UTC)
+
+	// Voo sem horário e sem assentos vendidos custa o preço base
+	flight := models.Flight{Price: 200, Capacity: 10, Seats: 10}
+	fare, _ := flight.Fare("")
+	if price := pricing.Price(flight, fare, now); price != 200 {
+		t.Errorf("expected base price 200, got %d", price)
+	}
+
+	// Com 80% dos assentos da classe ocupados, o acréscimo é de 25%
+	fare.Seats = 2
+	if price := pricing.Price(flight, fare, now); price != 250 {
+		t.Errorf("expected 250 at 80%% load, got %d", price)
+	}
+
+	// A dois dias da partida, o preço ainda sobe 30%
+	flight.Departure, flight.Arrival = now.Add(48*time.Hour), now.Add(50*time.Hour)
+	if price := pricing.Price(flight, fare, now); price != 325 {
+		t.Errorf("expected 325 two days before the departure, got %d", price)
+	}
+
+	// Longe da partida, só a ocupação conta
+	if price := pricing.Price(flight, fare, now.Add(-30*24*time.Hour)); price != 250 {
+		t.Errorf("expected 250 a month before the departure, got %d", price)
+	}
+
+	if price := (models.StaticPricing{}).Price(flight, fare, now); price != 200 {
+		t.Errorf("static pricing should keep the base price, got %d", price)
+	}
+	if models.NewPricingEngine("unknown") != nil {
+		t.Error("unknown pricing engine should be refused")
+	}
+}
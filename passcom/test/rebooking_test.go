@@ -0,0 +1,218 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// buyTicket stores a ticket of the client on the flight and returns its UniqueId.
+func buyTicket(t *testing.T, username string, flight *models.Flight, price uint) string {
+	t.Helper()
+	ticket := models.Ticket{ClientId: clientId(t, username), FlightId: flight.ID, UniqueId: uuid.NewString(), Price: price}
+	dao.GetTicketDAO().Insert(ticket)
+	return ticket.UniqueId
+}
+
+func rebookingsOf(t *testing.T, username string) []models.Rebooking {
+	t.Helper()
+	rebookings, err := dao.GetRebookingDAO().FindByClient(clientId(t, username))
+	if err != nil {
+		t.Fatalf("Expected rebookings of %s: %v", username, err)
+	}
+	return rebookings
+}
+
+// waitForRequests waits until the peer receives the given number of requests on the path.
+func waitForRequests(peer *fakePeer, path string, count int) int {
+	for i := 0; i < 50 && peer.requests(path) < count; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	return peer.requests(path)
+}
+
+func TestCancelFlightOffersNextItinerary(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	loginAs(t, "remarcado.cliente")
+
+	cancelled := ownFlightBetween(t, 94, 95, 3)
+	next := ownFlightBetween(t, 94, 95, 3)
+	ticketId := buyTicket(t, "remarcado.cliente", cancelled, 150)
+
+	// Um servidor que comprou um assento do voo pelo Two-Phase Commit
+	coordinator := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, coordinator)
+	dao.GetTransactionDAO().Insert(models.Transaction{
+		TransactionId: uuid.NewString(),
+		Type:          models.TypePurchase,
+		FlightId:      cancelled.UniqueId,
+		Role:          models.RoleParticipant,
+		Coordinator:   coordinator.id,
+		Status:        models.COMMITED,
+	})
+
+	if response := system.CancelFlight(cancelled.UniqueId); response.Status != http.StatusOK {
+		t.Fatalf("Expected flight to be cancelled, got %+v", response)
+	}
+
+	rebookings := rebookingsOf(t, "remarcado.cliente")
+	if len(rebookings) != 1 {
+		t.Fatalf("Expected 1 rebooking, got %d", len(rebookings))
+	}
+	rebooking := rebookings[0]
+	if rebooking.Status != models.RebookingOffered || rebooking.TicketId != ticketId || rebooking.Price != 150 {
+		t.Errorf("Expected offer for ticket %s paid 150, got %+v", ticketId, rebooking)
+	}
+	if len(rebooking.Itinerary) != 1 || rebooking.Itinerary[0] != next.UniqueId {
+		t.Errorf("Expected itinerary [%s], got %v", next.UniqueId, rebooking.Itinerary)
+	}
+	if _, err := dao.GetTicketDAO().FindByUniqueId(ticketId); err == nil {
+		t.Errorf("Expected cancelled ticket to be removed")
+	}
+
+	if received := coordinator.requests("/server/tickets/affected"); received != 1 {
+		t.Errorf("Expected coordinator to be told of its tickets once, got %d", received)
+	}
+}
+
+func TestCancelFlightRefundsWithoutItinerary(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	loginAs(t, "reembolsado.cliente")
+
+	// Cliente federado cuja companhia de origem é o par conectado
+	home := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, home)
+	dao.GetClientDAO().Insert(models.Client{Name: "Cliente Federado", Username: "federado.cliente@peer", HomeCompany: "peer"})
+
+	cancelled := ownFlightBetween(t, 96, 97, 3)
+	ticketId := buyTicket(t, "reembolsado.cliente", cancelled, 0)
+	buyTicket(t, "federado.cliente@peer", cancelled, 80)
+
+	if response := system.CancelFlight(cancelled.UniqueId); response.Status != http.StatusOK {
+		t.Fatalf("Expected flight to be cancelled, got %+v", response)
+	}
+
+	// Sem preço registrado, o reembolso é o preço do voo
+	rebookings := rebookingsOf(t, "reembolsado.cliente")
+	if len(rebookings) != 1 || rebookings[0].Status != models.RebookingRefunded || rebookings[0].Refund != cancelled.Price {
+		t.Fatalf("Expected refund of %d for ticket %s, got %+v", cancelled.Price, ticketId, rebookings)
+	}
+	if len(rebookings[0].Itinerary) != 0 {
+		t.Errorf("Expected no itinerary, got %v", rebookings[0].Itinerary)
+	}
+
+	federated := rebookingsOf(t, "federado.cliente@peer")
+	if len(federated) != 1 || federated[0].Refund != 80 {
+		t.Errorf("Expected refund of 80 for the federated client, got %+v", federated)
+	}
+
+	if received := waitForRequests(home, "/server/tickets/affected", 1); received != 1 {
+		t.Errorf("Expected home company to be told of its client's ticket once, got %d", received)
+	}
+}
+
+func sendAffectedTickets(id string, from string, affected models.AffectedTickets) int {
+	msg, _ := models.CreateMessage(from, system.ServerId.String(), map[string]int{from: 1}, affected)
+	msg.Id = id
+	body, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	system.HandleAffectedTickets(recorder, httptest.NewRequest(http.MethodPost, "/server/tickets/affected", bytes.NewReader(body)))
+	return recorder.Code
+}
+
+func TestAffectedTicketsOfHomeClient(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "casa.cliente")
+
+	peer := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, peer)
+
+	affected := models.AffectedTickets{
+		Flight:    models.Flight{Company: "other", UniqueId: uuid.NewString()},
+		TicketIds: []string{"refunded", "offered"},
+		Rebookings: map[string][]models.Rebooking{"casa.cliente": {
+			{TicketId: "refunded", Status: models.RebookingRefunded, Price: 80, Refund: 80},
+			{TicketId: "offered", Status: models.RebookingOffered, Price: 80, Itinerary: []string{uuid.NewString()}},
+		}},
+	}
+
+	if status := sendAffectedTickets(uuid.NewString(), uuid.NewString(), affected); status != http.StatusForbidden {
+		t.Errorf("Expected status %d for an unknown server, got %d", http.StatusForbidden, status)
+	}
+
+	id := uuid.NewString()
+	for i := 0; i < 2; i++ {
+		if status := sendAffectedTickets(id, peer.id, affected); status != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+		}
+	}
+
+	response := server.GetRebookings(models.Request{Auth: token})
+	rebookings, _ := response.Data["rebookings"].([]models.Rebooking)
+	if len(rebookings) != 2 {
+		t.Fatalf("Expected 2 rebookings recorded once, got %+v", rebookings)
+	}
+	for _, rebooking := range rebookings {
+		if rebooking.Server != "peer" {
+			t.Errorf("Expected rebooking of ticket bought on peer, got %+v", rebooking)
+		}
+		if rebooking.Status == models.RebookingOffered {
+			decision := server.DecideRebooking(rebooking.ID, models.Request{Auth: token, Data: models.RebookingDecision{Accept: true}})
+			if decision.Status != http.StatusConflict {
+				t.Errorf("Expected offer of another server not to be decided here, got %+v", decision)
+			}
+		}
+	}
+}
+
+func TestAffectedTicketsFromOwnerRebookTickets(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	loginAs(t, "coordenado.cliente")
+
+	owner := &fakePeer{id: uuid.NewString()}
+	connectFakePeer(t, owner)
+
+	// Réplica do voo do par, que não recebeu o broadcast do cancelamento
+	flight := models.Flight{
+		Company:              "peer",
+		UniqueId:             uuid.NewString(),
+		OriginAirportID:      98,
+		DestinationAirportID: 99,
+		Seats:                2,
+		Capacity:             3,
+		Price:                100,
+		Version:              1,
+		VectorClock:          map[string]int{owner.id: 1},
+	}
+	dao.GetFlightDAO().Insert(flight)
+	replica, _ := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId)
+	ticketId := buyTicket(t, "coordenado.cliente", replica, 120)
+
+	flight.Version = 2
+	flight.VectorClock = map[string]int{owner.id: 2}
+	flight.Status = models.FlightCancelled
+	flight.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+
+	if status := sendAffectedTickets(uuid.NewString(), owner.id, models.AffectedTickets{Flight: flight, TicketIds: []string{ticketId}}); status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if _, err := dao.GetFlightDAO().FindByUniqueId(flight.UniqueId); err == nil {
+		t.Errorf("Expected flight cancelled by its owner to be removed")
+	}
+	rebookings := rebookingsOf(t, "coordenado.cliente")
+	if len(rebookings) != 1 || rebookings[0].TicketId != ticketId || rebookings[0].Status != models.RebookingRefunded ||
+		rebookings[0].Refund != 120 || rebookings[0].Server != "" {
+		t.Errorf("Expected refund of 120 for ticket %s, got %+v", ticketId, rebookings)
+	}
+}
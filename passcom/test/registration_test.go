@@ -0,0 +1,35 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestValidateRegistration(t *testing.T) {
+	valid := models.Registration{Name: "João Silva", Username: "joao.silva_2", Password: "senhaSegura123"}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("expected valid registration, got %v", err)
+	}
+
+	cases := []struct {
+		registration models.Registration
+		err          error
+	}{
+		{models.Registration{Name: "  ", Username: "joao", Password: "senhaSegura123"}, models.ErrInvalidName},
+		{models.Registration{Name: strings.Repeat("a", 101), Username: "joao", Password: "senhaSegura123"}, models.ErrInvalidName},
+		{models.Registration{Name: "João", Username: "jo", Password: "senhaSegura123"}, models.ErrInvalidUsername},
+		{models.Registration{Name: "João", Username: "Joao", Password: "senhaSegura123"}, models.ErrInvalidUsername},
+		{models.Registration{Name: "João", Username: "joao silva", Password: "senhaSegura123"}, models.ErrInvalidUsername},
+		{models.Registration{Name: "João", Username: "joao", Password: "curta1"}, models.ErrInvalidPassword},
+		{models.Registration{Name: "João", Username: "joao", Password: "semdigitos"}, models.ErrInvalidPassword},
+		{models.Registration{Name: "João", Username: "joao", Password: "12345678"}, models.ErrInvalidPassword},
+		{models.Registration{Name: "João", Username: "joao", Password: strings.Repeat("a1", 37)}, models.ErrInvalidPassword},
+	}
+
+	for _, c := range cases {
+		if err := c.registration.Validate(); err != c.err {
+			t.Errorf("%+v: expected %v, got %v", c.registration, c.err, err)
+		}
+	}
+}
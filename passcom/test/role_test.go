@@ -0,0 +1,29 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"testing"
+)
+
+func TestValidRole(t *testing.T) {
+	for _, role := range []string{"customer", "agent", "airline-admin", "ops"} {
+		if !models.ValidRole(role) {
+			t.Errorf("expected %q to be a valid role", role)
+		}
+	}
+
+	for _, role := range []string{"", "admin", "Agent", "admin-token"} {
+		if models.ValidRole(role) {
+			t.Errorf("expected %q not to be a valid role", role)
+		}
+	}
+}
+
+func TestEffectiveRole(t *testing.T) {
+	if role := (models.Client{}).EffectiveRole(); role != models.RoleCustomer {
+		t.Errorf("expected client without role to be %s, got %s", models.RoleCustomer, role)
+	}
+	if role := (models.Client{Role: models.RoleOps}).EffectiveRole(); role != models.RoleOps {
+		t.Errorf("expected %s, got %s", models.RoleOps, role)
+	}
+}
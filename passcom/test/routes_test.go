@@ -0,0 +1,240 @@
+package test
+
+import (
+	"net/http"
+	"os"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"passcom/internal/utils"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Grafo de teste: 1 -> 4 possui cinco caminhos sem ciclos, além dos ciclos entre 2 e 3
+var routeFlights = []struct {
+	src, dest uint
+	price     uint
+	company   string
+}{
+	{1, 2, 100, "rumos"},
+	{2, 4, 100, "rumos"},
+	{1, 3, 50, "giro"},
+	{3, 4, 300, "giro"},
+	{1, 4, 400, "boreal"},
+	{2, 3, 10, "giro"},
+	{3, 2, 10, "boreal"},
+}
+
+// testDbPath is the database used by the tests, kept when the server is configured again.
+var testDbPath string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "passcom-test")
+	if err != nil {
+		panic(err)
+	}
+	testDbPath = filepath.Join(dir, "database.db")
+	utils.SetDbPath(testDbPath)
+
+	for i := 1; i <= 4; i++ {
+		dao.GetAirportDAO().Insert(models.Airport{Name: "Airport " + strconv.Itoa(i)})
+	}
+	for i, f := range routeFlights {
+		dao.GetFlightDAO().Insert(models.Flight{
+			Company:              f.company,
+			UniqueId:             strconv.Itoa(i),
+			Price:                f.price,
+			OriginAirportID:      f.src,
+			DestinationAirportID: f.dest,
+			Seats:                10,
+		})
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func price(previous *models.Flight, flight models.Flight) uint { return flight.Price }
+
+func pathAirports(paths [][]models.Flight) [][]uint {
+	result := make([][]uint, len(paths))
+	for i, path := range paths {
+		result[i] = []uint{path[0].OriginAirportID}
+		for _, flight := range path {
+			result[i] = append(result[i], flight.DestinationAirportID)
+		}
+	}
+	return result
+}
+
+func TestShortestPathsRankedByPrice(t *testing.T) {
+	paths, err := dao.GetFlightDAO().FindShortestPaths(1, 4, models.PathSearch{K: 10, Weight: price})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]uint{{1, 3, 2, 4}, {1, 2, 4}, {1, 3, 4}, {1, 4}, {1, 2, 3, 4}}
+	if got := pathAirports(paths); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestShortestPathsMaxLegs(t *testing.T) {
+	paths, err := dao.GetFlightDAO().FindShortestPaths(1, 4, models.PathSearch{K: 10, MaxLegs: 2, Weight: price})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]uint{{1, 2, 4}, {1, 3, 4}, {1, 4}}
+	if got := pathAirports(paths); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestShortestPathsCompanyFilter(t *testing.T) {
+	notBoreal := func(flight models.Flight) bool { return flight.Company != "boreal" }
+	paths, err := dao.GetFlightDAO().FindShortestPaths(1, 4, models.PathSearch{K: 2, Weight: price, Allow: notBoreal})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]uint{{1, 2, 4}, {1, 3, 4}}
+	if got := pathAirports(paths); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	onlyGiro := func(flight models.Flight) bool { return flight.Company == "giro" }
+	if _, err := dao.GetFlightDAO().FindShortestPaths(1, 2, models.PathSearch{Weight: price, Allow: onlyGiro}); err == nil {
+		t.Errorf("Expected no path using only giro flights")
+	}
+}
+
+func TestShortestPathsConnections(t *testing.T) {
+	// Só é possível seguir voos de companhias diferentes, o que deixa apenas 1 -> 3 -> 2 -> 4 e o voo direto
+	alternate := func(previous *models.Flight, flight models.Flight) bool {
+		return previous == nil || previous.Company != flight.Company
+	}
+	paths, err := dao.GetFlightDAO().FindShortestPaths(1, 4, models.PathSearch{K: 10, Weight: price, CanFollow: alternate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]uint{{1, 3, 2, 4}, {1, 4}}
+	if got := pathAirports(paths); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+// routeThrough searches the routes between two airports by name in the given mode.
+func routeThrough(t *testing.T, token string, src string, dest string, mode string) models.Response {
+	t.Helper()
+	return server.Route(models.Request{Auth: token, Data: models.RouteRequest{Source: src, Dest: dest, Mode: mode}})
+}
+
+func bestItinerary(t *testing.T, response models.Response) []uint {
+	t.Helper()
+	if response.Status != http.StatusOK {
+		t.Fatalf("Expected a route, got %+v", response)
+	}
+	path, _ := response.Data["paths"].([]models.Flight)
+	return pathAirports([][]models.Flight{path})[0]
+}
+
+func TestRouteModesOverPrices(t *testing.T) {
+	token := loginAs(t, "rotas.modos")
+
+	ids := make([]uint, 4)
+	for i, name := range []string{"Modo A", "Modo B", "Modo C", "Modo D"} {
+		dao.GetAirportDAO().Insert(models.Airport{Name: name})
+		ids[i] = dao.GetAirportDAO().FindByName(name).ID
+	}
+
+	flight := func(src, dest uint, price uint, company string) {
+		dao.GetFlightDAO().Insert(models.Flight{
+			Company:              company,
+			UniqueId:             uuid.NewString(),
+			OriginAirportID:      src,
+			DestinationAirportID: dest,
+			Seats:                10,
+			Price:                price,
+		})
+	}
+	// A -> D possui um voo direto e conexões de várias companhias, além dos ciclos entre B e C
+	flight(ids[0], ids[1], 100, "rumos")
+	flight(ids[1], ids[3], 100, "rumos")
+	flight(ids[0], ids[2], 50, "giro")
+	flight(ids[2], ids[3], 300, "giro")
+	flight(ids[0], ids[3], 400, "boreal")
+	flight(ids[1], ids[2], 10, "giro")
+	flight(ids[2], ids[1], 10, "boreal")
+
+	// A -> C -> B -> D custa 160, o menor preço entre os caminhos
+	cheapest := routeThrough(t, token, "Modo A", "Modo D", models.RouteCheapest)
+	if got := bestItinerary(t, cheapest); !reflect.DeepEqual(got, []uint{ids[0], ids[2], ids[1], ids[3]}) {
+		t.Errorf("Expected cheapest route through C and B, got %v", got)
+	}
+	if price := cheapest.Data["price"]; price != uint(160) {
+		t.Errorf("Expected total price 160, got %v", price)
+	}
+
+	fewest := routeThrough(t, token, "Modo A", "Modo D", models.RouteFewestHops)
+	if got := bestItinerary(t, fewest); !reflect.DeepEqual(got, []uint{ids[0], ids[3]}) {
+		t.Errorf("Expected direct route, got %v", got)
+	}
+	if legs := fewest.Data["legs"]; legs != 1 {
+		t.Errorf("Expected 1 leg, got %v", legs)
+	}
+
+	if response := routeThrough(t, token, "Modo A", "Modo D", "scenic"); response.Status != http.StatusBadRequest {
+		t.Errorf("Expected unknown mode to get %d, got %d", http.StatusBadRequest, response.Status)
+	}
+}
+
+func TestRouteFastestCountsConnections(t *testing.T) {
+	token := loginAs(t, "rotas.rapidas")
+
+	ids := make([]uint, 3)
+	for i, name := range []string{"Rápido A", "Rápido B", "Rápido C"} {
+		dao.GetAirportDAO().Insert(models.Airport{Name: name})
+		ids[i] = dao.GetAirportDAO().FindByName(name).ID
+	}
+
+	day := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	scheduled := func(src, dest uint, departure, arrival int, price uint) {
+		dao.GetFlightDAO().Insert(models.Flight{
+			Company:              "rumos",
+			UniqueId:             uuid.NewString(),
+			OriginAirportID:      src,
+			DestinationAirportID: dest,
+			Seats:                10,
+			Price:                price,
+			Departure:            day.Add(time.Duration(departure) * time.Hour),
+			Arrival:              day.Add(time.Duration(arrival) * time.Hour),
+		})
+	}
+	// O voo direto dura 12 horas; a conexão por B leva 6 horas, contando a espera
+	scheduled(ids[0], ids[2], 8, 20, 100)
+	scheduled(ids[0], ids[1], 8, 10, 150)
+	scheduled(ids[1], ids[2], 12, 14, 150)
+
+	fastest := routeThrough(t, token, "Rápido A", "Rápido C", models.RouteFastest)
+	if got := bestItinerary(t, fastest); !reflect.DeepEqual(got, []uint{ids[0], ids[1], ids[2]}) {
+		t.Errorf("Expected fastest route through B, got %v", got)
+	}
+	if duration := fastest.Data["duration"]; duration != (6 * time.Hour).String() {
+		t.Errorf("Expected 6h duration, got %v", duration)
+	}
+
+	for _, mode := range []string{models.RouteCheapest, models.RouteFewestHops} {
+		if got := bestItinerary(t, routeThrough(t, token, "Rápido A", "Rápido C", mode)); !reflect.DeepEqual(got, []uint{ids[0], ids[2]}) {
+			t.Errorf("Expected %s route to be the direct flight, got %v", mode, got)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"passcom/internal/models"
+	"testing"
+	"time"
+)
+
+var testSchedule = models.Schedule{
+	ScheduleId:           "rumos-mao-mcp",
+	Company:              "rumos",
+	OriginAirportID:      3,
+	DestinationAirportID: 4,
+	Weekdays:             []string{"Mon", "Wed", "Fri"},
+	DepartureTime:        "08:00",
+	Duration:             "3h30m",
+	OriginTimeZone:       "America/Manaus",
+	DestinationTimeZone:  "America/Belem",
+	Seats:                160,
+	Price:                350,
+	StartDate:            "2026-12-01",
+	EndDate:              "2026-12-31",
+}
+
+func TestScheduleFlightsBetween(t *testing.T) {
+	from := time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC)
+	flights := testSchedule.FlightsBetween(from, from.AddDate(0, 0, 7))
+
+	// 30/11 é segunda, mas antes do início; 02/12 e 04/12 são quarta e sexta
+	if len(flights) != 2 {
+		t.Fatalf("Expected 2 flights, got %d", len(flights))
+	}
+
+	first := flights[0]
+	if got := first.Departure.Format(time.RFC3339); got != "2026-12-02T08:00:00-04:00" {
+		t.Errorf("Expected departure at 08:00 in Manaus, got %s", got)
+	}
+	if got := first.Arrival.Format(time.RFC3339); got != "2026-12-02T12:30:00-03:00" {
+		t.Errorf("Expected arrival at 12:30 in Belém, got %s", got)
+	}
+	if flights[1].Departure.Weekday() != time.Friday {
+		t.Errorf("Expected the second flight on friday, got %v", flights[1].Departure.Weekday())
+	}
+}
+
+func TestScheduleStableUniqueIds(t *testing.T) {
+	from := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	a := testSchedule.FlightsBetween(from, from.AddDate(0, 0, 14))
+	b := testSchedule.FlightsBetween(from.Add(time.Hour), from.AddDate(0, 0, 14))
+
+	if len(a) != len(b) || len(a) == 0 {
+		t.Fatalf("Expected the same flights, got %d and %d", len(a), len(b))
+	}
+	for i := range a {
+		if a[i].UniqueId != b[i].UniqueId {
+			t.Errorf("Expected stable UniqueId, got %s and %s", a[i].UniqueId, b[i].UniqueId)
+		}
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	if err := testSchedule.Validate(); err != nil {
+		t.Errorf("Expected valid schedule, got %v", err)
+	}
+
+	invalid := testSchedule
+	invalid.Weekdays = []string{"Monday"}
+	if invalid.Validate() == nil {
+		t.Errorf("Expected invalid weekday to be rejected")
+	}
+
+	invalid = testSchedule
+	invalid.OriginTimeZone = "Mars/Olympus"
+	if invalid.Validate() == nil {
+		t.Errorf("Expected invalid time zone to be rejected")
+	}
+}
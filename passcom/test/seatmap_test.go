@@ -0,0 +1,100 @@
+package test
+
+import (
+	"net/http"
+	"passcom/internal/models"
+	"passcom/internal/server"
+	"sync"
+	"testing"
+)
+
+func TestLayoutSeats(t *testing.T) {
+	layout := models.Layout{
+		{Name: models.CabinBusiness, Rows: 2, Letters: "AC"},
+		{Name: models.CabinEconomy, Rows: 3, Letters: "ABC"},
+	}
+	if err := layout.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	seats := layout.Seats()
+	if len(seats) != layout.Count() || len(seats) != 13 {
+		t.Fatalf("expected 13 seats, got %d", len(seats))
+	}
+	// As fileiras continuam numeradas na cabine seguinte
+	if seats[0].Number != "1A" || seats[3].Number != "2C" || seats[4].Number != "3A" || seats[4].Cabin != models.CabinEconomy {
+		t.Errorf("unexpected seat order: %v", seats[:5])
+	}
+
+	if err := (models.Layout{{Name: models.CabinEconomy, Rows: 1, Letters: "AA"}}).Validate(); err == nil {
+		t.Error("repeated seat letters should be refused")
+	}
+}
+
+func TestFlightAssignSeat(t *testing.T) {
+	// Sem layout, o voo usa o layout padrão de seis assentos por fileira, limitado à capacidade
+	flight := models.Flight{Capacity: 8, Seats: 8}
+	if seats := flight.SeatMap(); len(seats) != 8 || seats[7].Number != "2B" {
+		t.Fatalf("unexpected seat map: %v", seats)
+	}
+
+	if seat, err := flight.AssignSeat("2B", ""); err != nil || seat != "2B" {
+		t.Fatalf("seat 2B not assigned: %v", err)
+	}
+	if _, err := flight.AssignSeat("2B", ""); err == nil {
+		t.Error("taken seat should be refused")
+	}
+	if _, err := flight.AssignSeat("2C", ""); err == nil {
+		t.Error("seat beyond the capacity should be refused")
+	}
+	if seat, _ := flight.AssignSeat("", ""); seat != "1A" {
+		t.Errorf("expected first free seat 1A, got %s", seat)
+	}
+	if flight.OccupiedSeats() != 2 || len(flight.Occupancy) != 1 {
+		t.Errorf("unexpected occupancy bitmap %v", flight.Occupancy)
+	}
+
+	flight.ReleaseSeat("2B")
+	if flight.OccupiedSeats() != 1 || flight.SeatMap()[7].Taken {
+		t.Error("seat 2B not released")
+	}
+}
+
+func TestConcurrentLocalPurchasesTakeDistinctSeats(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+	token := loginAs(t, "assentos.concorrentes")
+
+	flight := ownFlightBetween(t, 94, 95, 3)
+	statuses := make([]int, 6)
+	var wg sync.WaitGroup
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = server.BuyTicket(models.Request{Auth: token, Data: models.BuyTicket{FlightId: flight.ID}}).Status
+		}(i)
+	}
+	wg.Wait()
+
+	// Somente os assentos do voo são vendidos, cada um uma única vez
+	sold := 0
+	for _, status := range statuses {
+		if status == http.StatusOK {
+			sold++
+		}
+	}
+	tickets := ticketsOf(t, "assentos.concorrentes")
+	if sold != 3 || len(tickets) != 3 {
+		t.Fatalf("Expected 3 seats sold, got %d purchases and %d tickets", sold, len(tickets))
+	}
+	if tickets[0].Seat == tickets[1].Seat || tickets[0].Seat == tickets[2].Seat || tickets[1].Seat == tickets[2].Seat {
+		t.Errorf("Expected distinct seats, got %q, %q and %q", tickets[0].Seat, tickets[1].Seat, tickets[2].Seat)
+	}
+	if stored := seatsOf(t, flight); stored.Seats != 0 || stored.OccupiedSeats() != 3 {
+		t.Errorf("Expected no seats left and 3 occupied, got %d and %d", stored.Seats, stored.OccupiedSeats())
+	}
+
+	if response := server.BuyTicket(models.Request{Auth: token, Data: models.BuyTicket{FlightId: 1 << 30}}); response.Status != http.StatusNotFound {
+		t.Errorf("Expected unknown flight to get %d, got %d", http.StatusNotFound, response.Status)
+	}
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"passcom/internal/models"
+	"passcom/internal/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerifyToken(t *testing.T) {
+	seed := base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize))
+
+	for _, c := range []struct{ alg, key string }{{utils.TOKEN_HS256, "segredo"}, {utils.TOKEN_EDDSA, seed}} {
+		signer, err := utils.NewTokenSigner(c.alg, c.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		now := time.Now()
+		claims := models.AccessClaims{Subject: 7, Session: "device", Issuer: "rumos", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()}
+		token, err := signer.Sign(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var verified models.AccessClaims
+		if err := signer.Verify(token, &verified); err != nil || verified != claims {
+			t.Fatalf("%s: expected %+v, got %+v (%v)", c.alg, claims, verified, err)
+		}
+		if verified.Expired(now) || !verified.Expired(now.Add(time.Minute)) {
+			t.Errorf("%s: wrong expiration", c.alg)
+		}
+
+		// Alterar o payload invalida a assinatura
+		segments := strings.Split(token, ".")
+		forged, _ := utils.NewTokenSigner(utils.TOKEN_HS256, "outro")
+		other, _ := forged.Sign(models.AccessClaims{Subject: 1})
+		tampered := segments[0] + "." + strings.Split(other, ".")[1] + "." + segments[2]
+		if err := signer.Verify(tampered, &verified); err != utils.ErrInvalidToken {
+			t.Errorf("%s: tampered token should not be valid", c.alg)
+		}
+	}
+}
+
+func TestVerifyTokenOfOtherKey(t *testing.T) {
+	signer, _ := utils.NewTokenSigner(utils.TOKEN_HS256, "segredo")
+	other, _ := utils.NewTokenSigner(utils.TOKEN_HS256, "outro")
+
+	token, _ := other.Sign(models.AccessClaims{Subject: 1})
+	var claims models.AccessClaims
+	if err := signer.Verify(token, &claims); err != utils.ErrInvalidToken {
+		t.Error("token of another key should not be valid")
+	}
+
+	// O algoritmo do cabeçalho não pode trocar o da configuração
+	none := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	forged := none + "." + strings.Split(token, ".")[1] + "."
+	if err := signer.Verify(forged, &claims); err != utils.ErrInvalidToken {
+		t.Error("token without signature should not be valid")
+	}
+
+	if _, err := utils.NewTokenSigner(utils.TOKEN_EDDSA, ""); err == nil {
+		t.Error("EdDSA requires a key")
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"passcom/internal/dao"
+	"passcom/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func sendServerPurchase(id string, request models.SeatRequest) (int, models.SeatRequest) {
+	msg, _ := models.CreateMessage(uuid.NewString(), system.ServerId.String(), map[string]int{}, request)
+	msg.Id = id
+	body, _ := json.Marshal(msg)
+
+	recorder := httptest.NewRecorder()
+	system.HandleServerTicketPurchase(recorder, httptest.NewRequest(http.MethodPost, "/server/ticket/purchase", bytes.NewReader(body)))
+
+	var response models.Message
+	var seat models.SeatRequest
+	json.NewDecoder(recorder.Body).Decode(&response)
+	data, _ := json.Marshal(response.Body)
+	json.Unmarshal(data, &seat)
+	return recorder.Code, seat
+}
+
+func TestServerPurchaseCommitsParticipantTransaction(t *testing.T) {
+	t.Cleanup(resetVectorClock)
+
+	flight := ownFlightBetween(t, 92, 93, 1)
+	id := uuid.NewString()
+
+	status, seat := sendServerPurchase(id, models.SeatRequest{FlightId: flight.UniqueId})
+	if status != http.StatusOK || seat.Seat == "" || seat.Price == 0 {
+		t.Fatalf("Expected a seat to be sold, got %d %+v", status, seat)
+	}
+
+	// A compra é registrada como uma transação do participante decidida em um único passo
+	transaction, err := dao.GetTransactionDAO().FindByTransactionId(id)
+	if err != nil || transaction.Role != models.RoleParticipant || transaction.Status != models.COMMITED {
+		t.Fatalf("Expected a committed participant transaction, got %+v (%v)", transaction, err)
+	}
+	if transaction.Seat != seat.Seat {
+		t.Errorf("Expected transaction seat %q, got %q", seat.Seat, transaction.Seat)
+	}
+	if stored := seatsOf(t, flight); stored.Seats != 0 || stored.OccupiedSeats() != 1 {
+		t.Errorf("Expected the seat to be taken, got %d seats and %d occupied", stored.Seats, stored.OccupiedSeats())
+	}
+
+	if status, _ := sendServerPurchase(uuid.NewString(), models.SeatRequest{FlightId: flight.UniqueId}); status != http.StatusConflict {
+		t.Errorf("Expected full flight to get %d, got %d", http.StatusConflict, status)
+	}
+	if status, _ := sendServerPurchase(uuid.NewString(), models.SeatRequest{FlightId: uuid.NewString()}); status != http.StatusNotFound {
+		t.Errorf("Expected unknown flight to get %d, got %d", http.StatusNotFound, status)
+	}
+}
@@ -1,5 +0,0 @@
-package models
-
-type BuyTicket struct {
-	FlightId uint
-}
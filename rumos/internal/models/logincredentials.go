@@ -1,6 +0,0 @@
-package models
-
-type LoginCredentials struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-}
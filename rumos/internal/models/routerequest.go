@@ -1,6 +0,0 @@
-package models
-
-type RouteRequest struct {
-	Source string
-	Dest   string
-}